@@ -0,0 +1,256 @@
+package pixel
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// AnimationFrame is a single frame of an AnimationClip: a frame of a Picture shown for the
+// Duration in seconds.
+type AnimationFrame struct {
+	Picture  Picture
+	Frame    Rect
+	Duration float64
+}
+
+// PlayMode specifies the order in which the frames of an AnimationClip are played.
+type PlayMode int
+
+// Here's the list of all available PlayModes.
+const (
+	// PlayLoop plays the frames from the first to the last over and over.
+	PlayLoop PlayMode = iota
+
+	// PlayOnce plays the frames from the first to the last and then stays at the last frame.
+	PlayOnce
+
+	// PlayPingPong plays the frames from the first to the last and back over and over, without
+	// repeating the first and the last frame.
+	PlayPingPong
+
+	// PlayReverse plays the frames from the last to the first over and over.
+	PlayReverse
+)
+
+// String returns the name of the PlayMode.
+func (pm PlayMode) String() string {
+	switch pm {
+	case PlayLoop:
+		return "Loop"
+	case PlayOnce:
+		return "Once"
+	case PlayPingPong:
+		return "PingPong"
+	case PlayReverse:
+		return "Reverse"
+	default:
+		return fmt.Sprintf("PlayMode(%d)", int(pm))
+	}
+}
+
+// AnimationClip is a named animation of an AnimatedSprite.
+type AnimationClip struct {
+	Frames []AnimationFrame
+	Mode   PlayMode
+}
+
+// AnimatedSprite is a Sprite which changes its frames over time. It holds a set of named
+// AnimationClips and plays one of them at a time, advancing by the time passed to Update.
+//
+//   hero := pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{
+//       "idle": {Frames: idleFrames, Mode: pixel.PlayPingPong},
+//       "jump": {Frames: jumpFrames, Mode: pixel.PlayOnce},
+//   }, "idle")
+//   hero.OnFinish = func(clip string) { hero.Play("idle") }
+//
+//   // in the game loop
+//   hero.Update(dt)
+//   hero.Draw(win, pixel.IM.Moved(heroPos))
+type AnimatedSprite struct {
+	// Speed multiplies the time passed to Update. The default is 1, 0.5 plays at half the speed.
+	// Speed must not be negative.
+	Speed float64
+
+	// OnFrame, if not nil, is called whenever a frame becomes the current frame, with the name of
+	// the playing clip and the index of the frame in it.
+	OnFrame func(clip string, frame int)
+
+	// OnFinish, if not nil, is called when a clip played with PlayOnce reaches its end.
+	OnFinish func(clip string)
+
+	sprite *Sprite
+	clips  map[string]AnimationClip
+
+	clip    string
+	index   int
+	dir     int
+	elapsed float64
+	paused  bool
+	done    bool
+}
+
+// NewAnimatedSprite creates a new AnimatedSprite with the given clips, playing the start clip.
+//
+// NewAnimatedSprite panics if the start clip doesn't exist, if any of the clips has no frames, or
+// if any of the frames has a non-positive duration.
+func NewAnimatedSprite(clips map[string]AnimationClip, start string) *AnimatedSprite {
+	for name, clip := range clips {
+		if len(clip.Frames) == 0 {
+			panic(fmt.Errorf("NewAnimatedSprite: clip %q has no frames", name))
+		}
+		for i, f := range clip.Frames {
+			if f.Duration <= 0 {
+				panic(fmt.Errorf("NewAnimatedSprite: frame %d of clip %q has non-positive duration", i, name))
+			}
+		}
+	}
+	if _, ok := clips[start]; !ok {
+		panic(fmt.Errorf("NewAnimatedSprite: no clip named %q", start))
+	}
+
+	first := clips[start].Frames[0]
+	as := &AnimatedSprite{
+		Speed:  1,
+		sprite: NewSprite(first.Picture, first.Frame),
+		clips:  clips,
+		clip:   start,
+	}
+	as.Restart()
+	return as
+}
+
+// Play switches to the named clip and plays it from the start. If the clip is already playing,
+// Play only resumes it if it's paused, so it's fine to call it on every frame of the game.
+//
+// Play panics if there's no clip with the given name.
+func (as *AnimatedSprite) Play(clip string) {
+	if _, ok := as.clips[clip]; !ok {
+		panic(fmt.Errorf("(%T).Play: no clip named %q", as, clip))
+	}
+	if clip == as.clip && !as.done {
+		as.paused = false
+		return
+	}
+	as.clip = clip
+	as.Restart()
+}
+
+// Restart plays the current clip from the start.
+func (as *AnimatedSprite) Restart() {
+	as.index, as.dir = 0, 1
+	if as.clips[as.clip].Mode == PlayReverse {
+		as.index, as.dir = len(as.clips[as.clip].Frames)-1, -1
+	}
+	as.elapsed = 0
+	as.paused = false
+	as.done = false
+	as.show()
+}
+
+// Pause stops the playback at the current frame until Resume or Play is called.
+func (as *AnimatedSprite) Pause() {
+	as.paused = true
+}
+
+// Resume continues the playback stopped by Pause.
+func (as *AnimatedSprite) Resume() {
+	as.paused = false
+}
+
+// Paused returns whether the playback is paused.
+func (as *AnimatedSprite) Paused() bool {
+	return as.paused
+}
+
+// Done returns whether the current clip played with PlayOnce has reached its end. Clips played
+// with the other modes never end.
+func (as *AnimatedSprite) Done() bool {
+	return as.done
+}
+
+// Clip returns the name of the current clip.
+func (as *AnimatedSprite) Clip() string {
+	return as.clip
+}
+
+// FrameIndex returns the index of the current frame in the current clip.
+func (as *AnimatedSprite) FrameIndex() int {
+	return as.index
+}
+
+// Sprite returns the underlying Sprite, which shows the current frame.
+func (as *AnimatedSprite) Sprite() *Sprite {
+	return as.sprite
+}
+
+// Update advances the animation by dt seconds, scaled by the Speed. It may skip several frames
+// if dt is long enough.
+func (as *AnimatedSprite) Update(dt float64) {
+	if as.paused || as.done {
+		return
+	}
+	as.elapsed += dt * as.Speed
+	for !as.done {
+		duration := as.clips[as.clip].Frames[as.index].Duration
+		if as.elapsed < duration {
+			break
+		}
+		as.elapsed -= duration
+		as.advance()
+	}
+}
+
+// advance moves to the next frame according to the PlayMode of the current clip.
+func (as *AnimatedSprite) advance() {
+	clip := as.clips[as.clip]
+	n := len(clip.Frames)
+
+	switch clip.Mode {
+	case PlayLoop, PlayReverse:
+		as.index = (as.index + as.dir + n) % n
+	case PlayOnce:
+		if as.index == n-1 {
+			as.done = true
+			as.elapsed = 0
+			if as.OnFinish != nil {
+				as.OnFinish(as.clip)
+			}
+			return
+		}
+		as.index++
+	case PlayPingPong:
+		if n == 1 {
+			return
+		}
+		if as.index+as.dir < 0 || as.index+as.dir >= n {
+			as.dir = -as.dir
+		}
+		as.index += as.dir
+	default:
+		panic(fmt.Errorf("(%T).Update: invalid PlayMode %v", as, clip.Mode))
+	}
+	as.show()
+}
+
+// show sets the Sprite to the current frame.
+func (as *AnimatedSprite) show() {
+	f := as.clips[as.clip].Frames[as.index]
+	as.sprite.Set(f.Picture, f.Frame)
+	if as.OnFrame != nil {
+		as.OnFrame(as.clip, as.index)
+	}
+}
+
+// Draw draws the current frame onto the provided Target. The frame will be transformed by the
+// given Matrix.
+func (as *AnimatedSprite) Draw(t Target, matrix Matrix) {
+	as.sprite.Draw(t, matrix)
+}
+
+// DrawColorMask draws the current frame onto the provided Target. The frame will be transformed
+// by the given Matrix and all of its color will be multiplied by the given mask.
+//
+// If the mask is nil, a fully opaque white mask will be used, which causes no effect.
+func (as *AnimatedSprite) DrawColorMask(t Target, matrix Matrix, mask color.Color) {
+	as.sprite.DrawColorMask(t, matrix, mask)
+}
@@ -0,0 +1,132 @@
+package pixel_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func animationFrames(n int, duration float64) []pixel.AnimationFrame {
+	pic := pixel.MakePictureData(pixel.R(0, 0, float64(n)*10, 10))
+	frames := make([]pixel.AnimationFrame, n)
+	for i := range frames {
+		frames[i] = pixel.AnimationFrame{
+			Picture:  pic,
+			Frame:    pixel.R(float64(i)*10, 0, float64(i+1)*10, 10),
+			Duration: duration,
+		}
+	}
+	return frames
+}
+
+// playedFrames returns the indices of the frames shown in the consecutive steps of dt.
+func playedFrames(as *pixel.AnimatedSprite, steps int, dt float64) []int {
+	played := []int{as.FrameIndex()}
+	for i := 0; i < steps; i++ {
+		as.Update(dt)
+		played = append(played, as.FrameIndex())
+	}
+	return played
+}
+
+func TestAnimatedSprite_Modes(t *testing.T) {
+	tests := []struct {
+		mode   pixel.PlayMode
+		played []int
+	}{
+		{pixel.PlayLoop, []int{0, 1, 2, 0, 1, 2, 0}},
+		{pixel.PlayOnce, []int{0, 1, 2, 2, 2, 2, 2}},
+		{pixel.PlayPingPong, []int{0, 1, 2, 1, 0, 1, 2}},
+		{pixel.PlayReverse, []int{2, 1, 0, 2, 1, 0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			as := pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{
+				"clip": {Frames: animationFrames(3, 0.1), Mode: tt.mode},
+			}, "clip")
+			assert.Equal(t, tt.played, playedFrames(as, 6, 0.1+1e-9))
+			assert.Equal(t, tt.mode == pixel.PlayOnce, as.Done())
+			assert.Equal(t, pixel.R(float64(as.FrameIndex())*10, 0, float64(as.FrameIndex()+1)*10, 10), as.Sprite().Frame())
+		})
+	}
+}
+
+func TestAnimatedSprite_Timing(t *testing.T) {
+	frames := animationFrames(3, 0.1)
+	frames[1].Duration = 0.3
+	as := pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{
+		"clip": {Frames: frames},
+	}, "clip")
+
+	assert.Equal(t, []int{0, 0, 1, 1, 1, 1, 1, 1, 2, 2, 0}, playedFrames(as, 10, 0.05+1e-9))
+
+	// long steps skip frames
+	as.Restart()
+	as.Update(0.45)
+	assert.Equal(t, 2, as.FrameIndex())
+
+	as.Restart()
+	as.Speed = 2
+	as.Update(0.1)
+	assert.Equal(t, 1, as.FrameIndex())
+
+	as.Pause()
+	as.Update(10)
+	assert.True(t, as.Paused())
+	assert.Equal(t, 1, as.FrameIndex())
+	as.Resume()
+	as.Update(0.1)
+	assert.Equal(t, 2, as.FrameIndex())
+}
+
+func TestAnimatedSprite_Clips(t *testing.T) {
+	as := pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{
+		"idle": {Frames: animationFrames(2, 0.5)},
+		"jump": {Frames: animationFrames(3, 0.1), Mode: pixel.PlayOnce},
+	}, "idle")
+
+	type event struct {
+		clip  string
+		frame int
+	}
+	var (
+		events   []event
+		finished []string
+	)
+	as.OnFrame = func(clip string, frame int) { events = append(events, event{clip, frame}) }
+	as.OnFinish = func(clip string) {
+		finished = append(finished, clip)
+		as.Play("idle")
+	}
+
+	as.Update(0.6)
+	as.Play("jump")
+	assert.Equal(t, "jump", as.Clip())
+	as.Update(0.15)
+	// playing the same clip doesn't restart it
+	as.Play("jump")
+	assert.Equal(t, 1, as.FrameIndex())
+	as.Update(0.2)
+
+	assert.Equal(t, []string{"jump"}, finished)
+	assert.Equal(t, "idle", as.Clip())
+	assert.Equal(t, []event{
+		{"idle", 1},
+		{"jump", 0},
+		{"jump", 1},
+		{"jump", 2},
+		{"idle", 0},
+	}, events)
+
+	assert.Panics(t, func() { as.Play("run") })
+	assert.Panics(t, func() {
+		pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{"idle": {}}, "idle")
+	})
+	assert.Panics(t, func() {
+		pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{"idle": {Frames: animationFrames(1, 0)}}, "idle")
+	})
+	assert.Panics(t, func() {
+		pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{"idle": {Frames: animationFrames(1, 1)}}, "run")
+	})
+}
@@ -0,0 +1,205 @@
+// Package atlas implements packing many Pictures into a few large pages, so that sprites using
+// them can be drawn together in a single Batch, and loading sprite sheets packed by other tools.
+package atlas
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// Algorithm is a method of packing rectangles into the pages of an Atlas.
+type Algorithm int
+
+// Here's the list of all available Algorithms. MaxRects packs tighter, Skyline is faster. See the
+// Bins of the same names for details.
+const (
+	MaxRectsAlgorithm Algorithm = iota
+	SkylineAlgorithm
+)
+
+// Options configure how an Atlas is packed.
+type Options struct {
+	// PageWidth and PageHeight are the maximal size of a page in pixels. If they're zero, 2048 is
+	// used. Pages are trimmed to the smallest size containing all of their frames.
+	PageWidth, PageHeight int
+
+	// Padding is the number of transparent pixels between the frames.
+	Padding int
+
+	// Extrude is the number of times the edge pixels of each frame are repeated around it. It
+	// prevents the neighboring frames and the transparent padding from bleeding into the edges of
+	// sprites drawn smooth or at fractional positions.
+	Extrude int
+
+	// Algorithm is the Algorithm used for packing.
+	Algorithm Algorithm
+}
+
+// Frame is the location of a packed Picture inside an Atlas.
+type Frame struct {
+	// Page is the index of the page in the Atlas's Pages.
+	Page int
+
+	// Rect is the frame of the Picture in the page, ready to be passed to pixel.NewSprite.
+	Rect pixel.Rect
+}
+
+// Atlas is a set of pages containing the packed Pictures, along with the Frames telling where
+// each of them ended up.
+//
+//   sheet, err := atlas.Pack(pictures, atlas.Options{Padding: 1, Extrude: 1})
+//   if err != nil {
+//       panic(err)
+//   }
+//   batch := pixel.NewBatch(&pixel.TrianglesData{}, sheet.Pages[0])
+//   sheet.Sprite("player").Draw(batch, pixel.IM.Moved(pos))
+type Atlas struct {
+	Pages  []*pixel.PictureData
+	Frames map[string]Frame
+}
+
+// Sprite creates a new Sprite of the named frame of the Atlas.
+//
+// Sprite panics if there's no such frame in the Atlas.
+func (a *Atlas) Sprite(name string) *pixel.Sprite {
+	f, ok := a.Frames[name]
+	if !ok {
+		panic(fmt.Errorf("(%T).Sprite: no frame named %q", a, name))
+	}
+	return pixel.NewSprite(a.Pages[f.Page], f.Rect)
+}
+
+// Pack packs the named Pictures into the pages of a new Atlas. The Pictures are converted to
+// PictureData, so Pictures without PictureColor end up transparent.
+//
+// Pack returns an error if one of the Pictures doesn't fit into a page.
+func Pack(pictures map[string]pixel.Picture, opts Options) (*Atlas, error) {
+	if opts.PageWidth == 0 {
+		opts.PageWidth = 2048
+	}
+	if opts.PageHeight == 0 {
+		opts.PageHeight = 2048
+	}
+	if opts.PageWidth < 0 || opts.PageHeight < 0 || opts.Padding < 0 || opts.Extrude < 0 {
+		return nil, fmt.Errorf("atlas: negative options %+v", opts)
+	}
+
+	type item struct {
+		name string
+		pd   *pixel.PictureData
+		w, h int
+	}
+	items := make([]item, 0, len(pictures))
+	for name, pic := range pictures {
+		pd := pixel.PictureDataFromPicture(pic)
+		var w, h int
+		if pd.Stride > 0 {
+			w, h = pd.Stride, len(pd.Pix)/pd.Stride
+		}
+		items = append(items, item{name, pd, w, h})
+	}
+	// big items first pack much better, names make the result deterministic
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.h != b.h {
+			return a.h > b.h
+		}
+		if a.w != b.w {
+			return a.w > b.w
+		}
+		return a.name < b.name
+	})
+
+	// the bins are bigger by the padding, so that there's no padding after the last frames
+	newBin := func() Bin {
+		if opts.Algorithm == SkylineAlgorithm {
+			return NewSkyline(opts.PageWidth+opts.Padding, opts.PageHeight+opts.Padding)
+		}
+		return NewMaxRects(opts.PageWidth+opts.Padding, opts.PageHeight+opts.Padding)
+	}
+
+	type placement struct {
+		item
+		page, x, y int
+	}
+	var (
+		bins       []Bin
+		placements []placement
+	)
+	for _, it := range items {
+		slotW := it.w + 2*opts.Extrude + opts.Padding
+		slotH := it.h + 2*opts.Extrude + opts.Padding
+		placed := false
+		for page, bin := range bins {
+			if x, y, ok := bin.Insert(slotW, slotH); ok {
+				placements = append(placements, placement{it, page, x, y})
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+		bin := newBin()
+		x, y, ok := bin.Insert(slotW, slotH)
+		if !ok {
+			return nil, fmt.Errorf("atlas: picture %q (%dx%d) doesn't fit into a %dx%d page",
+				it.name, it.w, it.h, opts.PageWidth, opts.PageHeight)
+		}
+		bins = append(bins, bin)
+		placements = append(placements, placement{it, len(bins) - 1, x, y})
+	}
+
+	// trim the pages to the used area
+	sizes := make([][2]int, len(bins))
+	for _, p := range placements {
+		if w := p.x + p.w + 2*opts.Extrude; w > sizes[p.page][0] {
+			sizes[p.page][0] = w
+		}
+		if h := p.y + p.h + 2*opts.Extrude; h > sizes[p.page][1] {
+			sizes[p.page][1] = h
+		}
+	}
+
+	a := &Atlas{
+		Pages:  make([]*pixel.PictureData, len(bins)),
+		Frames: make(map[string]Frame, len(placements)),
+	}
+	for i, size := range sizes {
+		a.Pages[i] = pixel.MakePictureData(pixel.R(0, 0, float64(size[0]), float64(size[1])))
+	}
+	for _, p := range placements {
+		page := a.Pages[p.page]
+		x, y := p.x+opts.Extrude, p.y+opts.Extrude
+		blit(page, x, y, p.pd, p.w, p.h, opts.Extrude)
+		a.Frames[p.name] = Frame{
+			Page: p.page,
+			Rect: pixel.R(float64(x), float64(y), float64(x+p.w), float64(y+p.h)),
+		}
+	}
+	return a, nil
+}
+
+// blit copies the pixels of the PictureData of the given size onto the page at (x, y), with its
+// edge pixels repeated extrude times around it.
+func blit(page *pixel.PictureData, x, y int, pd *pixel.PictureData, w, h, extrude int) {
+	if w == 0 || h == 0 {
+		return
+	}
+	clamp := func(v, max int) int {
+		if v < 0 {
+			return 0
+		}
+		if v >= max {
+			return max - 1
+		}
+		return v
+	}
+	for dy := -extrude; dy < h+extrude; dy++ {
+		for dx := -extrude; dx < w+extrude; dx++ {
+			page.Pix[(y+dy)*page.Stride+x+dx] = pd.Pix[clamp(dy, h)*pd.Stride+clamp(dx, w)]
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package atlas_test
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/atlas"
+	"github.com/stretchr/testify/assert"
+)
+
+// solid returns a PictureData of the given size filled with the color, except for its bottom-left
+// pixel which is white.
+func solid(w, h int, c color.RGBA) *pixel.PictureData {
+	pd := pixel.MakePictureData(pixel.R(0, 0, float64(w), float64(h)))
+	for i := range pd.Pix {
+		pd.Pix[i] = c
+	}
+	pd.Pix[0] = color.RGBA{255, 255, 255, 255}
+	return pd
+}
+
+func TestPack(t *testing.T) {
+	pictures := map[string]pixel.Picture{}
+	for i := 0; i < 20; i++ {
+		c := color.RGBA{uint8(i * 10), 100, 200, 255}
+		pictures[fmt.Sprint(i)] = solid(5+i%7, 3+i%5, c)
+	}
+
+	for _, algo := range []atlas.Algorithm{atlas.MaxRectsAlgorithm, atlas.SkylineAlgorithm} {
+		a, err := atlas.Pack(pictures, atlas.Options{
+			PageWidth:  64,
+			PageHeight: 64,
+			Padding:    2,
+			Extrude:    1,
+			Algorithm:  algo,
+		})
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Len(t, a.Frames, len(pictures))
+
+		for name, f := range a.Frames {
+			pic := pictures[name].(*pixel.PictureData)
+			page := a.Pages[f.Page]
+			assert.True(t, page.Rect.Contains(f.Rect.Min) && page.Rect.Contains(f.Rect.Max))
+			assert.Equal(t, pic.Rect.Size(), f.Rect.Size())
+
+			// the pixels are copied, with the white corner staying at the bottom-left
+			assert.Equal(t, color.RGBA{255, 255, 255, 255}, page.Pix[page.Index(f.Rect.Min)])
+			inside := f.Rect.Max.Sub(pixel.V(0.5, 0.5))
+			assert.Equal(t, pic.Pix[1], page.Pix[page.Index(inside)])
+			// and the edges are extruded
+			assert.Equal(t, color.RGBA{255, 255, 255, 255}, page.Pix[page.Index(f.Rect.Min.Sub(pixel.V(1, 1)))])
+			assert.Equal(t, pic.Pix[1], page.Pix[page.Index(inside.Add(pixel.V(1, 1)))])
+
+			// extruded frames with their padding don't overlap
+			for other, g := range a.Frames {
+				if other == name || g.Page != f.Page {
+					continue
+				}
+				grown := pixel.Rect{Min: f.Rect.Min.Sub(pixel.V(2, 2)), Max: f.Rect.Max.Add(pixel.V(2, 2))}
+				assert.False(t, grown.Intersects(g.Rect), "%s and %s overlap", name, other)
+			}
+
+			sprite := a.Sprite(name)
+			assert.Equal(t, f.Rect, sprite.Frame())
+			assert.Equal(t, page, sprite.Picture())
+		}
+	}
+}
+
+func TestPack_Pages(t *testing.T) {
+	pictures := map[string]pixel.Picture{}
+	for i := 0; i < 5; i++ {
+		pictures[fmt.Sprint(i)] = solid(20, 20, color.RGBA{A: 255})
+	}
+
+	a, err := atlas.Pack(pictures, atlas.Options{PageWidth: 40, PageHeight: 40})
+	assert.NoError(t, err)
+	assert.Len(t, a.Pages, 2)
+	// the second page only holds a single frame, so it's trimmed
+	assert.Equal(t, pixel.R(0, 0, 20, 20), a.Pages[1].Rect)
+
+	_, err = atlas.Pack(map[string]pixel.Picture{"big": solid(50, 10, color.RGBA{})}, atlas.Options{PageWidth: 40, PageHeight: 40})
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { a.Sprite("missing") })
+}
@@ -0,0 +1,211 @@
+package atlas
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bin packs rectangles into a fixed-size area, so that they don't overlap each other.
+//
+// Positions are in the same orientation as in Pixel, that is, the y-axis goes up and (0, 0) is the
+// bottom-left corner of the area.
+type Bin interface {
+	// Insert finds a free place for a rectangle of the given size and marks it as used. It returns
+	// the position of the bottom-left corner of the rectangle, or false if there's no free place
+	// big enough.
+	Insert(w, h int) (x, y int, ok bool)
+}
+
+// rect is an integer rectangle with its corner at (x, y).
+type rect struct {
+	x, y, w, h int
+}
+
+func (r rect) contains(s rect) bool {
+	return r.x <= s.x && r.y <= s.y && s.x+s.w <= r.x+r.w && s.y+s.h <= r.y+r.h
+}
+
+func (r rect) intersects(s rect) bool {
+	return r.x < s.x+s.w && s.x < r.x+r.w && r.y < s.y+s.h && s.y < r.y+r.h
+}
+
+// MaxRects is a Bin implementing the MaxRects algorithm with the best short side fit heuristic.
+// It keeps track of all maximal free rectangles, so it packs tightly even rectangles of very
+// different sizes, but inserting is slower than into a Skyline.
+type MaxRects struct {
+	free []rect
+}
+
+// NewMaxRects creates a new empty MaxRects Bin of the given size.
+//
+// NewMaxRects panics if the size is negative.
+func NewMaxRects(w, h int) *MaxRects {
+	if w < 0 || h < 0 {
+		panic(fmt.Errorf("NewMaxRects: negative size %dx%d", w, h))
+	}
+	return &MaxRects{free: []rect{{0, 0, w, h}}}
+}
+
+// Insert finds a free place for a rectangle of the given size and marks it as used. It returns
+// the position of the bottom-left corner of the rectangle, or false if there's no free place big
+// enough.
+func (mr *MaxRects) Insert(w, h int) (x, y int, ok bool) {
+	best := -1
+	bestShort, bestLong := math.MaxInt32, math.MaxInt32
+	for i, f := range mr.free {
+		if w > f.w || h > f.h {
+			continue
+		}
+		short, long := f.w-w, f.h-h
+		if short > long {
+			short, long = long, short
+		}
+		if short < bestShort || (short == bestShort && long < bestLong) {
+			best, bestShort, bestLong = i, short, long
+		}
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+
+	placed := rect{mr.free[best].x, mr.free[best].y, w, h}
+	if w > 0 && h > 0 {
+		mr.split(placed)
+	}
+	return placed.x, placed.y, true
+}
+
+// split replaces all free rectangles overlapping the used one with their parts around it.
+func (mr *MaxRects) split(used rect) {
+	var free []rect
+	for _, f := range mr.free {
+		if !f.intersects(used) {
+			free = append(free, f)
+			continue
+		}
+		if used.x > f.x {
+			free = append(free, rect{f.x, f.y, used.x - f.x, f.h})
+		}
+		if used.x+used.w < f.x+f.w {
+			free = append(free, rect{used.x + used.w, f.y, f.x + f.w - used.x - used.w, f.h})
+		}
+		if used.y > f.y {
+			free = append(free, rect{f.x, f.y, f.w, used.y - f.y})
+		}
+		if used.y+used.h < f.y+f.h {
+			free = append(free, rect{f.x, used.y + used.h, f.w, f.y + f.h - used.y - used.h})
+		}
+	}
+
+	// remove the free rectangles contained in others, they're never better
+	mr.free = mr.free[:0]
+	for i, f := range free {
+		redundant := false
+		for j, g := range free {
+			if i != j && g.contains(f) && (f != g || j < i) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			mr.free = append(mr.free, f)
+		}
+	}
+}
+
+// Skyline is a Bin implementing the skyline bottom-left algorithm. It only remembers the top edge
+// of the used area, which makes it fast and good for rectangles of similar heights, such as
+// glyphs, but it wastes the space under the taller rectangles.
+type Skyline struct {
+	w, h     int
+	segments []rect // h is unused, y is the height of the skyline
+}
+
+// NewSkyline creates a new empty Skyline Bin of the given size.
+//
+// NewSkyline panics if the size is negative.
+func NewSkyline(w, h int) *Skyline {
+	if w < 0 || h < 0 {
+		panic(fmt.Errorf("NewSkyline: negative size %dx%d", w, h))
+	}
+	return &Skyline{w: w, h: h, segments: []rect{{0, 0, w, 0}}}
+}
+
+// Insert finds a free place for a rectangle of the given size and marks it as used. It returns
+// the position of the bottom-left corner of the rectangle, or false if there's no free place big
+// enough.
+func (s *Skyline) Insert(w, h int) (x, y int, ok bool) {
+	best := -1
+	bestTop, bestWidth := math.MaxInt32, math.MaxInt32
+	for i, seg := range s.segments {
+		y, ok := s.fit(i, w, h)
+		if !ok {
+			continue
+		}
+		if y+h < bestTop || (y+h == bestTop && seg.w < bestWidth) {
+			best, bestTop, bestWidth = i, y+h, seg.w
+		}
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+
+	x, y = s.segments[best].x, bestTop-h
+	if w > 0 && h > 0 {
+		s.add(best, rect{x: x, y: y + h, w: w})
+	}
+	return x, y, true
+}
+
+// fit returns the height at which a rectangle of the given size fits on the skyline, starting at
+// the i-th segment.
+func (s *Skyline) fit(i, w, h int) (y int, ok bool) {
+	x := s.segments[i].x
+	if x+w > s.w {
+		return 0, false
+	}
+	// the segments cover the whole width, so there's always enough of them
+	y = s.segments[i].y
+	for left := w; left > 0; i++ {
+		if s.segments[i].y > y {
+			y = s.segments[i].y
+		}
+		left -= s.segments[i].w
+	}
+	if y+h > s.h {
+		return 0, false
+	}
+	return y, true
+}
+
+// add inserts the new segment at the i-th position, cutting away the segments under it.
+func (s *Skyline) add(i int, seg rect) {
+	s.segments = append(s.segments, rect{})
+	copy(s.segments[i+1:], s.segments[i:])
+	s.segments[i] = seg
+
+	end := seg.x + seg.w
+	for j := i + 1; j < len(s.segments); {
+		next := &s.segments[j]
+		if next.x >= end {
+			break
+		}
+		if next.x+next.w <= end {
+			s.segments = append(s.segments[:j], s.segments[j+1:]...)
+			continue
+		}
+		next.w -= end - next.x
+		next.x = end
+		break
+	}
+
+	// merge the neighbors of the same height
+	for j := 0; j+1 < len(s.segments); {
+		if s.segments[j].y == s.segments[j+1].y {
+			s.segments[j].w += s.segments[j+1].w
+			s.segments = append(s.segments[:j+1], s.segments[j+2:]...)
+			continue
+		}
+		j++
+	}
+}
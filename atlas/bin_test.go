@@ -0,0 +1,50 @@
+package atlas_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel/atlas"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBin_Insert(t *testing.T) {
+	bins := map[string]func(w, h int) atlas.Bin{
+		"MaxRects": func(w, h int) atlas.Bin { return atlas.NewMaxRects(w, h) },
+		"Skyline":  func(w, h int) atlas.Bin { return atlas.NewSkyline(w, h) },
+	}
+	for name, newBin := range bins {
+		t.Run(name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(0))
+			bin := newBin(256, 256)
+
+			type rect struct{ x, y, w, h int }
+			var placed []rect
+			area := 0
+			for i := 0; i < 500; i++ {
+				w, h := 1+rnd.Intn(32), 1+rnd.Intn(32)
+				x, y, ok := bin.Insert(w, h)
+				if !ok {
+					continue
+				}
+				r := rect{x, y, w, h}
+				assert.True(t, x >= 0 && y >= 0 && x+w <= 256 && y+h <= 256, "%v out of the bin", r)
+				for _, s := range placed {
+					overlap := r.x < s.x+s.w && s.x < r.x+r.w && r.y < s.y+s.h && s.y < r.y+r.h
+					assert.False(t, overlap, "%v overlaps %v", r, s)
+				}
+				placed = append(placed, r)
+				area += w * h
+			}
+			// both algorithms should fill most of the bin
+			assert.Greater(t, area, 256*256*3/4)
+
+			// a full-size rectangle fits exactly into an empty bin
+			x, y, ok := newBin(64, 32).Insert(64, 32)
+			assert.True(t, ok)
+			assert.Equal(t, [2]int{0, 0}, [2]int{x, y})
+			_, _, ok = newBin(64, 32).Insert(65, 1)
+			assert.False(t, ok)
+		})
+	}
+}
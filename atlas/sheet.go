@@ -0,0 +1,294 @@
+package atlas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/faiface/pixel"
+)
+
+// Sheet is a sprite sheet exported by a tool such as TexturePacker or Aseprite: a list of named
+// frames inside a single image, and optionally the animations made from them.
+//
+// All of the coordinates are converted to the coordinates of the PictureData loaded from the
+// sheet's image, that is, the y-axis goes up and (0, 0) is the bottom-left corner of the image.
+//
+//   sheet, err := atlas.DecodeSheet(file)
+//   if err != nil {
+//       panic(err)
+//   }
+//   f, _ := sheet.Frame("hero_idle_0")
+//   f.Sprite(pic).Draw(win, f.Matrix().Moved(heroPos))
+type Sheet struct {
+	// Image is the file name of the sheet's image, as written in the sheet.
+	Image string
+
+	// Size is the size of the sheet's image in pixels.
+	Size pixel.Vec
+
+	// Frames are the frames in the order they appear in the sheet.
+	Frames []SheetFrame
+
+	// Tags are the named animations of the sheet.
+	Tags []Tag
+}
+
+// SheetFrame is a single frame of a Sheet.
+type SheetFrame struct {
+	// Name is the name of the frame, usually the file name of the original sprite.
+	Name string
+
+	// Rect is the frame in the sheet's image, ready to be passed to pixel.NewSprite.
+	Rect pixel.Rect
+
+	// Rotated tells that the frame is stored in the sheet rotated 90 degrees clockwise, and its
+	// Rect is rotated the same way. Matrix takes care of rotating it back.
+	Rotated bool
+
+	// SourceSize is the size of the original sprite, before the transparent borders were trimmed
+	// from it.
+	SourceSize pixel.Vec
+
+	// Offset is the position of the center of the frame relative to the center of the original
+	// sprite. It's zero unless the sprite was trimmed.
+	Offset pixel.Vec
+
+	// Pivot is the position of the pivot point relative to the center of the original sprite.
+	Pivot pixel.Vec
+
+	// Duration is the duration of the frame in an animation in seconds, or zero if the sheet
+	// doesn't specify it.
+	Duration float64
+}
+
+// Tag is a named animation made of a range of frames of a Sheet.
+type Tag struct {
+	Name string
+
+	// From and To are the indices of the first and the last frame of the animation in the Sheet's
+	// Frames, both inclusive.
+	From, To int
+
+	// Direction is the direction of playback: "forward", "reverse", "pingpong" or
+	// "pingpong_reverse".
+	Direction string
+
+	// Repeat is how many times the animation should play, zero means forever.
+	Repeat int
+}
+
+// Frame returns the frame of the Sheet with the given name.
+func (s *Sheet) Frame(name string) (SheetFrame, bool) {
+	for _, f := range s.Frames {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return SheetFrame{}, false
+}
+
+// Tag returns the Tag of the Sheet with the given name.
+func (s *Sheet) Tag(name string) (Tag, bool) {
+	for _, t := range s.Tags {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Tag{}, false
+}
+
+// TagFrames returns the frames of the Tag in the order of the Sheet's Frames.
+func (s *Sheet) TagFrames(t Tag) []SheetFrame {
+	return s.Frames[t.From : t.To+1]
+}
+
+// Clip creates an AnimationClip of the frames of the Tag with the sheet's Picture. The Direction
+// of the Tag selects the PlayMode, "pingpong_reverse" plays as ping-pong starting from the last
+// frame.
+//
+// Frames must have their durations specified by the sheet to be played by an AnimatedSprite.
+func (s *Sheet) Clip(pic pixel.Picture, t Tag) pixel.AnimationClip {
+	frames := s.TagFrames(t)
+	clip := pixel.AnimationClip{Frames: make([]pixel.AnimationFrame, len(frames))}
+	for i, f := range frames {
+		if t.Direction == "pingpong_reverse" {
+			f = frames[len(frames)-1-i]
+		}
+		clip.Frames[i] = pixel.AnimationFrame{Picture: pic, Frame: f.Rect, Duration: f.Duration}
+	}
+	switch t.Direction {
+	case "reverse":
+		clip.Mode = pixel.PlayReverse
+	case "pingpong", "pingpong_reverse":
+		clip.Mode = pixel.PlayPingPong
+	default:
+		clip.Mode = pixel.PlayLoop
+	}
+	return clip
+}
+
+// Sprite creates a new Sprite of the frame of the sheet's Picture.
+func (f SheetFrame) Sprite(pic pixel.Picture) *pixel.Sprite {
+	return pixel.NewSprite(pic, f.Rect)
+}
+
+// Matrix returns the Matrix which places the Sprite of the frame where the original sprite would
+// be, with its pivot at the origin. Chain it with the Matrix positioning the sprite.
+func (f SheetFrame) Matrix() pixel.Matrix {
+	m := pixel.IM
+	if f.Rotated {
+		m = m.Rotated(pixel.ZV, math.Pi/2)
+	}
+	return m.Moved(f.Offset.Sub(f.Pivot))
+}
+
+// the JSON format shared by TexturePacker and Aseprite
+type jsonRect struct {
+	X, Y, W, H float64
+}
+
+type jsonFrame struct {
+	Filename         string
+	Frame            jsonRect
+	Rotated          bool
+	SpriteSourceSize *jsonRect
+	SourceSize       *jsonRect
+	Pivot            *struct{ X, Y float64 }
+	Duration         float64
+}
+
+type jsonSheet struct {
+	Frames json.RawMessage
+	Meta   struct {
+		Image     string
+		Size      *jsonRect
+		FrameTags []struct {
+			Name      string
+			From, To  int
+			Direction string
+			Repeat    string
+		}
+	}
+}
+
+// DecodeSheet decodes a sprite sheet from the JSON format exported by TexturePacker (both the
+// hash and the array variant) and Aseprite.
+//
+// The frames' positions are flipped to the coordinates of PictureData using the size of the image
+// stored in the sheet, so a sheet without it is an error. Frames in the hash variant keep the
+// order in which they're written.
+func DecodeSheet(r io.Reader) (*Sheet, error) {
+	var js jsonSheet
+	if err := json.NewDecoder(r).Decode(&js); err != nil {
+		return nil, fmt.Errorf("atlas: decoding sheet: %v", err)
+	}
+	if js.Meta.Size == nil {
+		return nil, fmt.Errorf("atlas: decoding sheet: missing meta.size")
+	}
+
+	frames, err := decodeFrames(js.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("atlas: decoding sheet: %v", err)
+	}
+
+	s := &Sheet{
+		Image: js.Meta.Image,
+		Size:  pixel.V(js.Meta.Size.W, js.Meta.Size.H),
+	}
+	for _, jf := range frames {
+		s.Frames = append(s.Frames, sheetFrame(jf, s.Size.Y))
+	}
+
+	for _, jt := range js.Meta.FrameTags {
+		if jt.From < 0 || jt.To < jt.From || jt.To >= len(s.Frames) {
+			return nil, fmt.Errorf("atlas: decoding sheet: tag %q has invalid frames %d-%d", jt.Name, jt.From, jt.To)
+		}
+		t := Tag{Name: jt.Name, From: jt.From, To: jt.To, Direction: jt.Direction}
+		if t.Direction == "" {
+			t.Direction = "forward"
+		}
+		if jt.Repeat != "" {
+			t.Repeat, err = strconv.Atoi(jt.Repeat)
+			if err != nil {
+				return nil, fmt.Errorf("atlas: decoding sheet: tag %q has invalid repeat %q", jt.Name, jt.Repeat)
+			}
+		}
+		s.Tags = append(s.Tags, t)
+	}
+
+	return s, nil
+}
+
+// decodeFrames decodes the frames either from an array, or from an object keeping the order of
+// its keys.
+func decodeFrames(data json.RawMessage) ([]jsonFrame, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing frames")
+	}
+	if data[0] == '[' {
+		var frames []jsonFrame
+		err := json.Unmarshal(data, &frames)
+		return frames, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("frames must be an array or an object")
+	}
+	var frames []jsonFrame
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		jf := jsonFrame{Filename: tok.(string)}
+		if err := dec.Decode(&jf); err != nil {
+			return nil, err
+		}
+		frames = append(frames, jf)
+	}
+	return frames, nil
+}
+
+// sheetFrame converts the frame from the y-down coordinates of the sheet to the y-up coordinates
+// of PictureData.
+func sheetFrame(jf jsonFrame, height float64) SheetFrame {
+	// rotated frames are written with their original size
+	w, h := jf.Frame.W, jf.Frame.H
+	if jf.Rotated {
+		w, h = h, w
+	}
+	f := SheetFrame{
+		Name:     jf.Filename,
+		Rect:     pixel.R(jf.Frame.X, height-jf.Frame.Y-h, jf.Frame.X+w, height-jf.Frame.Y),
+		Rotated:  jf.Rotated,
+		Duration: jf.Duration / 1000,
+	}
+
+	// the sprite's size and its position inside the original sprite
+	trimmed := jsonRect{W: jf.Frame.W, H: jf.Frame.H}
+	if jf.SpriteSourceSize != nil {
+		trimmed = *jf.SpriteSourceSize
+	}
+	f.SourceSize = pixel.V(trimmed.W, trimmed.H)
+	if jf.SourceSize != nil {
+		f.SourceSize = pixel.V(jf.SourceSize.W, jf.SourceSize.H)
+	}
+	f.Offset = pixel.V(
+		trimmed.X+trimmed.W/2-f.SourceSize.X/2,
+		f.SourceSize.Y/2-trimmed.Y-trimmed.H/2,
+	)
+
+	if jf.Pivot != nil {
+		f.Pivot = pixel.V(
+			(jf.Pivot.X-0.5)*f.SourceSize.X,
+			(0.5-jf.Pivot.Y)*f.SourceSize.Y,
+		)
+	}
+	return f
+}
@@ -0,0 +1,164 @@
+package atlas_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/atlas"
+	"github.com/stretchr/testify/assert"
+)
+
+const texturePackerHash = `{
+	"frames": {
+		"tree.png": {
+			"frame": {"x": 2, "y": 2, "w": 40, "h": 60},
+			"rotated": false,
+			"trimmed": true,
+			"spriteSourceSize": {"x": 10, "y": 4, "w": 40, "h": 60},
+			"sourceSize": {"w": 64, "h": 64},
+			"pivot": {"x": 0.5, "y": 1}
+		},
+		"arrow.png": {
+			"frame": {"x": 50, "y": 2, "w": 30, "h": 10},
+			"rotated": true,
+			"trimmed": false,
+			"spriteSourceSize": {"x": 0, "y": 0, "w": 30, "h": 10},
+			"sourceSize": {"w": 30, "h": 10},
+			"pivot": {"x": 0.5, "y": 0.5}
+		}
+	},
+	"meta": {"image": "sheet.png", "size": {"w": 128, "h": 64}}
+}`
+
+const texturePackerArray = `{
+	"frames": [
+		{
+			"filename": "coin.png",
+			"frame": {"x": 0, "y": 0, "w": 16, "h": 16},
+			"rotated": false,
+			"trimmed": false,
+			"spriteSourceSize": {"x": 0, "y": 0, "w": 16, "h": 16},
+			"sourceSize": {"w": 16, "h": 16}
+		}
+	],
+	"meta": {"image": "coins.png", "size": {"w": 32, "h": 32}}
+}`
+
+const aseprite = `{
+	"frames": {
+		"hero 0.aseprite": {"frame": {"x": 0, "y": 0, "w": 16, "h": 24}, "duration": 100},
+		"hero 1.aseprite": {"frame": {"x": 16, "y": 0, "w": 16, "h": 24}, "duration": 150},
+		"hero 2.aseprite": {"frame": {"x": 32, "y": 0, "w": 16, "h": 24}, "duration": 100}
+	},
+	"meta": {
+		"app": "https://www.aseprite.org/",
+		"image": "hero.png",
+		"size": {"w": 48, "h": 24},
+		"frameTags": [
+			{"name": "idle", "from": 0, "to": 0, "direction": "forward"},
+			{"name": "walk", "from": 1, "to": 2, "direction": "pingpong", "repeat": "3"}
+		]
+	}
+}`
+
+func TestDecodeSheet_TexturePacker(t *testing.T) {
+	sheet, err := atlas.DecodeSheet(strings.NewReader(texturePackerHash))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "sheet.png", sheet.Image)
+	assert.Equal(t, pixel.V(128, 64), sheet.Size)
+	if !assert.Len(t, sheet.Frames, 2) {
+		return
+	}
+	// the order of the hash is preserved
+	assert.Equal(t, "tree.png", sheet.Frames[0].Name)
+	assert.Equal(t, "arrow.png", sheet.Frames[1].Name)
+
+	tree, ok := sheet.Frame("tree.png")
+	assert.True(t, ok)
+	assert.Equal(t, pixel.R(2, 2, 42, 62), tree.Rect)
+	assert.False(t, tree.Rotated)
+	assert.Equal(t, pixel.V(64, 64), tree.SourceSize)
+	// the trimmed tree is right of the center and right at the bottom
+	assert.Equal(t, pixel.V(-2, -2), tree.Offset)
+	assert.Equal(t, pixel.V(0, -32), tree.Pivot)
+	assert.Equal(t, pixel.V(-2, 30), tree.Matrix().Project(pixel.ZV))
+
+	arrow, ok := sheet.Frame("arrow.png")
+	assert.True(t, ok)
+	assert.True(t, arrow.Rotated)
+	assert.Equal(t, pixel.R(50, 32, 60, 62), arrow.Rect)
+	assert.Equal(t, pixel.ZV, arrow.Offset)
+	// rotated back, the arrow is wide again
+	corner := arrow.Matrix().Project(pixel.V(5, 15))
+	assert.InDelta(t, -15, corner.X, 1e-9)
+	assert.InDelta(t, 5, corner.Y, 1e-9)
+
+	_, ok = sheet.Frame("missing.png")
+	assert.False(t, ok)
+
+	pic := pixel.MakePictureData(pixel.R(0, 0, 128, 64))
+	assert.Equal(t, tree.Rect, tree.Sprite(pic).Frame())
+
+	sheet, err = atlas.DecodeSheet(strings.NewReader(texturePackerArray))
+	if assert.NoError(t, err) && assert.Len(t, sheet.Frames, 1) {
+		assert.Equal(t, "coin.png", sheet.Frames[0].Name)
+		assert.Equal(t, pixel.R(0, 16, 16, 32), sheet.Frames[0].Rect)
+		assert.Equal(t, pixel.ZV, sheet.Frames[0].Offset)
+		assert.Equal(t, pixel.ZV, sheet.Frames[0].Pivot)
+		assert.Equal(t, pixel.IM, sheet.Frames[0].Matrix())
+	}
+}
+
+func TestDecodeSheet_Aseprite(t *testing.T) {
+	sheet, err := atlas.DecodeSheet(strings.NewReader(aseprite))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, sheet.Frames, 3)
+	assert.Equal(t, pixel.R(16, 0, 32, 24), sheet.Frames[1].Rect)
+	assert.Equal(t, pixel.V(16, 24), sheet.Frames[1].SourceSize)
+	assert.InDelta(t, 0.15, sheet.Frames[1].Duration, 1e-9)
+
+	walk, ok := sheet.Tag("walk")
+	assert.True(t, ok)
+	assert.Equal(t, atlas.Tag{Name: "walk", From: 1, To: 2, Direction: "pingpong", Repeat: 3}, walk)
+	frames := sheet.TagFrames(walk)
+	if assert.Len(t, frames, 2) {
+		assert.Equal(t, "hero 1.aseprite", frames[0].Name)
+		assert.Equal(t, "hero 2.aseprite", frames[1].Name)
+	}
+	_, ok = sheet.Tag("jump")
+	assert.False(t, ok)
+
+	pic := pixel.MakePictureData(pixel.R(0, 0, 48, 24))
+	clip := sheet.Clip(pic, walk)
+	assert.Equal(t, pixel.PlayPingPong, clip.Mode)
+	assert.Equal(t, []pixel.AnimationFrame{
+		{Picture: pic, Frame: pixel.R(16, 0, 32, 24), Duration: 0.15},
+		{Picture: pic, Frame: pixel.R(32, 0, 48, 24), Duration: 0.1},
+	}, clip.Frames)
+
+	idle, _ := sheet.Tag("idle")
+	hero := pixel.NewAnimatedSprite(map[string]pixel.AnimationClip{
+		"idle": sheet.Clip(pic, idle),
+		"walk": clip,
+	}, "walk")
+	assert.Equal(t, pixel.R(16, 0, 32, 24), hero.Sprite().Frame())
+}
+
+func TestDecodeSheet_Errors(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid json":   `{"frames": [`,
+		"missing size":   `{"frames": []}`,
+		"missing frames": `{"meta": {"size": {"w": 1, "h": 1}}}`,
+		"invalid frames": `{"frames": 3, "meta": {"size": {"w": 1, "h": 1}}}`,
+		"invalid tag":    `{"frames": [], "meta": {"size": {"w": 1, "h": 1}, "frameTags": [{"name": "a", "from": 0, "to": 1}]}}`,
+		"invalid repeat": `{"frames": [{"frame": {"w": 1, "h": 1}}], "meta": {"size": {"w": 1, "h": 1}, "frameTags": [{"name": "a", "from": 0, "to": 0, "repeat": "x"}]}}`,
+	} {
+		_, err := atlas.DecodeSheet(strings.NewReader(data))
+		assert.Error(t, err, name)
+	}
+}
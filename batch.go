@@ -9,6 +9,8 @@ import (
 //
 // To put an object into a Batch, just draw it onto it:
 //   object.Draw(batch)
+//
+// All objects must use the Batch's Picture. Use MultiBatch for objects with different Pictures.
 type Batch struct {
 	cont Drawer
 
@@ -25,6 +27,10 @@ var _ BasicTarget = (*Batch)(nil)
 // change it, call Dirty to notify Batch about the change.
 //
 // Note, that if the container does not support TrianglesColor, color masking will not work.
+//
+// If the container is IndexedTrianglesData (or other indexed TrianglesIndexed with a SetIndices
+// method), the objects keep their vertices shared. Otherwise, the shared vertices of indexed
+// objects are duplicated.
 func NewBatch(container Triangles, pic Picture) *Batch {
 	b := &Batch{cont: Drawer{Triangles: container, Picture: pic, Cached: true}}
 	b.SetMatrix(IM)
@@ -46,6 +52,11 @@ func (b *Batch) Dirty() {
 // Clear removes all objects from the Batch.
 func (b *Batch) Clear() {
 	b.cont.Triangles.SetLen(0)
+	if ic, ok := b.cont.Triangles.(indexedContainer); ok {
+		if indices, is := ic.Indices(); is {
+			ic.SetIndices(indices[:0])
+		}
+	}
 	b.cont.Dirty()
 }
 
@@ -90,6 +101,12 @@ func (b *Batch) MakePicture(p Picture) TargetPicture {
 	return bp
 }
 
+// indexedContainer is a container of a Batch which can accumulate indexed objects.
+type indexedContainer interface {
+	TrianglesIndexed
+	SetIndices(indices []int)
+}
+
 type batchTriangles struct {
 	tri Triangles
 	tmp *TrianglesData
@@ -125,19 +142,51 @@ func (bt *batchTriangles) Copy() Triangles {
 	}
 }
 
+func (bt *batchTriangles) Indices() (indices []int, is bool) {
+	return indicesOf(bt.tri)
+}
+
 func (bt *batchTriangles) draw(bp *batchPicture) {
-	bt.tmp.Update(bt.tri)
+	cont := bt.dst.cont.Triangles
+	ic, keepIndices := cont.(indexedContainer)
+	if keepIndices {
+		_, keepIndices = ic.Indices()
+	}
+
+	indices, indexed := indicesOf(bt.tri)
+	if indexed && !keepIndices {
+		bt.tmp = unindex(bt.tmp, bt.tri, indices)
+	} else {
+		bt.tmp.SetLen(bt.tri.Len())
+		bt.tmp.Update(bt.tri)
+	}
 
 	for i := range *bt.tmp {
 		(*bt.tmp)[i].Position = bt.dst.mat.Project((*bt.tmp)[i].Position)
 		(*bt.tmp)[i].Color = bt.dst.col.Mul((*bt.tmp)[i].Color)
 	}
 
-	cont := bt.dst.cont.Triangles
-	cont.SetLen(cont.Len() + bt.tri.Len())
-	added := cont.Slice(cont.Len()-bt.tri.Len(), cont.Len())
-	added.Update(bt.tri)
+	n := cont.Len()
+	cont.SetLen(n + bt.tmp.Len())
+	added := cont.Slice(n, cont.Len())
+	if !indexed {
+		added.Update(bt.tri)
+	}
 	added.Update(bt.tmp)
+
+	if keepIndices {
+		contIndices, _ := ic.Indices()
+		if indexed {
+			for _, i := range indices {
+				contIndices = append(contIndices, n+i)
+			}
+		} else {
+			for i := 0; i < bt.tmp.Len(); i++ {
+				contIndices = append(contIndices, n+i)
+			}
+		}
+		ic.SetIndices(contIndices)
+	}
 	bt.dst.cont.Dirty()
 }
 
@@ -0,0 +1,285 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+// QuadBezier is a quadratic Bezier curve going from P0 to P2 and bent towards the control point
+// P1.
+type QuadBezier struct {
+	P0, P1, P2 Vec
+}
+
+// CubicBezier is a cubic Bezier curve going from P0 to P3, with P1 and P2 as its control points.
+//
+// The curve leaves P0 in the direction of P1 and arrives to P3 from the direction of P2:
+//
+//   c := pixel.CubicBezier{P0: pixel.V(0, 0), P1: pixel.V(0, 100), P2: pixel.V(100, 100), P3: pixel.V(100, 0)}
+//   c.At(0.5)         // Vec(50, 75)
+//   c.Flatten(0.25)   // points along the curve, good for drawing with IMDraw
+type CubicBezier struct {
+	P0, P1, P2, P3 Vec
+}
+
+// String returns the string representation of the QuadBezier.
+//
+//   q := pixel.QuadBezier{pixel.V(0, 0), pixel.V(1, 1), pixel.V(2, 0)}
+//   q.String()     // returns "QuadBezier(Vec(0, 0), Vec(1, 1), Vec(2, 0))"
+//   fmt.Println(q) // QuadBezier(Vec(0, 0), Vec(1, 1), Vec(2, 0))
+func (q QuadBezier) String() string {
+	return fmt.Sprintf("QuadBezier(%v, %v, %v)", q.P0, q.P1, q.P2)
+}
+
+// At returns the point on the curve at the parameter t. The curve starts at t=0 and ends at t=1.
+func (q QuadBezier) At(t float64) Vec {
+	u := 1 - t
+	return q.P0.Scaled(u * u).Add(q.P1.Scaled(2 * u * t)).Add(q.P2.Scaled(t * t))
+}
+
+// Derivative returns the derivative of the curve at the parameter t. It points in the direction
+// of the curve and its length is the speed at which the point At(t) moves with t.
+func (q QuadBezier) Derivative(t float64) Vec {
+	return q.P0.To(q.P1).Scaled(2 * (1 - t)).Add(q.P1.To(q.P2).Scaled(2 * t))
+}
+
+// Tangent returns the unit vector facing the direction of the curve at the parameter t.
+func (q QuadBezier) Tangent(t float64) Vec {
+	return q.Cubic().Tangent(t)
+}
+
+// Split splits the curve at the parameter t into two curves, which together form the original
+// curve.
+func (q QuadBezier) Split(t float64) (QuadBezier, QuadBezier) {
+	a := Lerp(q.P0, q.P1, t)
+	b := Lerp(q.P1, q.P2, t)
+	m := Lerp(a, b, t)
+	return QuadBezier{q.P0, a, m}, QuadBezier{m, b, q.P2}
+}
+
+// Cubic returns the cubic Bezier curve describing exactly the same curve.
+func (q QuadBezier) Cubic() CubicBezier {
+	return CubicBezier{
+		P0: q.P0,
+		P1: Lerp(q.P0, q.P1, 2.0/3),
+		P2: Lerp(q.P2, q.P1, 2.0/3),
+		P3: q.P2,
+	}
+}
+
+// Len returns the length of the curve. The result is an estimate, which is very precise for all
+// curves except for those with sharp cusps.
+func (q QuadBezier) Len() float64 {
+	return q.Cubic().Len()
+}
+
+// Bounds returns the smallest Rect which contains the whole curve. The Rect is normalized.
+func (q QuadBezier) Bounds() Rect {
+	return q.Cubic().Bounds()
+}
+
+// Closest returns the point on the curve which is closest to the Vec provided, along with its
+// parameter t.
+func (q QuadBezier) Closest(v Vec) (Vec, float64) {
+	return q.Cubic().Closest(v)
+}
+
+// Flatten approximates the curve with a polyline, such that no point of the curve is further than
+// tolerance from the polyline. The first and the last point of the polyline are the end points of
+// the curve.
+func (q QuadBezier) Flatten(tolerance float64) []Vec {
+	return q.Cubic().Flatten(tolerance)
+}
+
+// String returns the string representation of the CubicBezier.
+//
+//   c := pixel.CubicBezier{pixel.V(0, 0), pixel.V(0, 1), pixel.V(1, 1), pixel.V(1, 0)}
+//   c.String()     // returns "CubicBezier(Vec(0, 0), Vec(0, 1), Vec(1, 1), Vec(1, 0))"
+//   fmt.Println(c) // CubicBezier(Vec(0, 0), Vec(0, 1), Vec(1, 1), Vec(1, 0))
+func (c CubicBezier) String() string {
+	return fmt.Sprintf("CubicBezier(%v, %v, %v, %v)", c.P0, c.P1, c.P2, c.P3)
+}
+
+// At returns the point on the curve at the parameter t. The curve starts at t=0 and ends at t=1.
+func (c CubicBezier) At(t float64) Vec {
+	u := 1 - t
+	return c.P0.Scaled(u * u * u).
+		Add(c.P1.Scaled(3 * u * u * t)).
+		Add(c.P2.Scaled(3 * u * t * t)).
+		Add(c.P3.Scaled(t * t * t))
+}
+
+// Derivative returns the derivative of the curve at the parameter t. It points in the direction
+// of the curve and its length is the speed at which the point At(t) moves with t.
+func (c CubicBezier) Derivative(t float64) Vec {
+	u := 1 - t
+	return c.P0.To(c.P1).Scaled(3 * u * u).
+		Add(c.P1.To(c.P2).Scaled(6 * u * t)).
+		Add(c.P2.To(c.P3).Scaled(3 * t * t))
+}
+
+// Tangent returns the unit vector facing the direction of the curve at the parameter t.
+//
+// If the derivative is zero at t (which happens at the end points when they coincide with their
+// control points), the direction is taken from the nearest non-degenerate control point.
+func (c CubicBezier) Tangent(t float64) Vec {
+	if d := c.Derivative(t); d != ZV {
+		return d.Unit()
+	}
+	switch {
+	case t <= 0 && c.P0 != c.P2:
+		return c.P0.To(c.P2).Unit()
+	case t >= 1 && c.P1 != c.P3:
+		return c.P1.To(c.P3).Unit()
+	}
+	return c.P0.To(c.P3).Unit()
+}
+
+// Split splits the curve at the parameter t into two curves, which together form the original
+// curve.
+func (c CubicBezier) Split(t float64) (CubicBezier, CubicBezier) {
+	ab := Lerp(c.P0, c.P1, t)
+	bc := Lerp(c.P1, c.P2, t)
+	cd := Lerp(c.P2, c.P3, t)
+	abc := Lerp(ab, bc, t)
+	bcd := Lerp(bc, cd, t)
+	m := Lerp(abc, bcd, t)
+	return CubicBezier{c.P0, ab, abc, m}, CubicBezier{m, bcd, cd, c.P3}
+}
+
+// Len returns the length of the curve. The result is an estimate, which is very precise for all
+// curves except for those with sharp cusps.
+func (c CubicBezier) Len() float64 {
+	// 8-point Gauss-Legendre quadrature of the speed on each of the evenly spaced pieces
+	const pieces = 8
+	var (
+		nodes   = [...]float64{0.1834346424956498, 0.5255324099163290, 0.7966664774136267, 0.9602898564975363}
+		weights = [...]float64{0.3626837833783620, 0.3137066458778873, 0.2223810344533745, 0.1012285362903763}
+	)
+	var length float64
+	for i := 0; i < pieces; i++ {
+		mid := (float64(i) + 0.5) / pieces
+		half := 0.5 / pieces
+		for j := range nodes {
+			length += weights[j] * half * c.Derivative(mid-half*nodes[j]).Len()
+			length += weights[j] * half * c.Derivative(mid+half*nodes[j]).Len()
+		}
+	}
+	return length
+}
+
+// Bounds returns the smallest Rect which contains the whole curve. The Rect is normalized.
+func (c CubicBezier) Bounds() Rect {
+	r := R(c.P0.X, c.P0.Y, c.P3.X, c.P3.Y).Norm()
+	for _, t := range c.extrema() {
+		p := c.At(t)
+		r = r.Union(Rect{Min: p, Max: p})
+	}
+	return r
+}
+
+// extrema returns the parameters in range (0, 1) at which the derivative of the curve is zero in
+// either of the axes.
+func (c CubicBezier) extrema() []float64 {
+	var ts []float64
+	for _, axis := range [...]func(Vec) float64{
+		func(v Vec) float64 { return v.X },
+		func(v Vec) float64 { return v.Y },
+	} {
+		p0, p1, p2, p3 := axis(c.P0), axis(c.P1), axis(c.P2), axis(c.P3)
+		// the derivative divided by 3 is a*t^2 + b*t + k
+		a := -p0 + 3*p1 - 3*p2 + p3
+		b := 2 * (p0 - 2*p1 + p2)
+		k := p1 - p0
+		for _, t := range quadraticRoots(a, b, k) {
+			if 0 < t && t < 1 {
+				ts = append(ts, t)
+			}
+		}
+	}
+	return ts
+}
+
+// quadraticRoots returns the real roots of a*x^2 + b*x + c = 0.
+func quadraticRoots(a, b, c float64) []float64 {
+	if math.Abs(a) < 1e-12 {
+		if b == 0 {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return nil
+	}
+	sqrt := math.Sqrt(disc)
+	return []float64{(-b + sqrt) / (2 * a), (-b - sqrt) / (2 * a)}
+}
+
+// Closest returns the point on the curve which is closest to the Vec provided, along with its
+// parameter t.
+func (c CubicBezier) Closest(v Vec) (Vec, float64) {
+	// find the closest of evenly spaced samples first, then refine it with Newton's method
+	const samples = 32
+	best, bestT := math.Inf(+1), 0.0
+	for i := 0; i <= samples; i++ {
+		t := float64(i) / samples
+		if d := c.At(t).To(v).SqLen(); d < best {
+			best, bestT = d, t
+		}
+	}
+
+	t := bestT
+	for i := 0; i < 16; i++ {
+		// minimize |At(t) - v|^2, whose derivative is 2 * (At(t) - v) . Derivative(t)
+		diff := v.To(c.At(t))
+		d1 := c.Derivative(t)
+		d2 := c.secondDerivative(t)
+		num := diff.Dot(d1)
+		den := d1.Dot(d1) + diff.Dot(d2)
+		if den == 0 {
+			break
+		}
+		next := Clamp(t-num/den, 0, 1)
+		if math.Abs(next-t) < 1e-12 {
+			t = next
+			break
+		}
+		t = next
+	}
+	if c.At(t).To(v).SqLen() > best {
+		t = bestT
+	}
+	return c.At(t), t
+}
+
+func (c CubicBezier) secondDerivative(t float64) Vec {
+	a := c.P0.To(c.P1).To(c.P1.To(c.P2))
+	b := c.P1.To(c.P2).To(c.P2.To(c.P3))
+	return a.Scaled(6 * (1 - t)).Add(b.Scaled(6 * t))
+}
+
+// Flatten approximates the curve with a polyline, such that no point of the curve is further than
+// tolerance from the polyline. The first and the last point of the polyline are the end points of
+// the curve.
+//
+// Flatten panics if the tolerance is not positive.
+func (c CubicBezier) Flatten(tolerance float64) []Vec {
+	if tolerance <= 0 {
+		panic(fmt.Errorf("(%T).Flatten: tolerance must be positive", c))
+	}
+	points := []Vec{c.P0}
+	return c.flatten(points, tolerance, 0)
+}
+
+func (c CubicBezier) flatten(points []Vec, tolerance float64, depth int) []Vec {
+	// the curve lies within the convex hull of its control points, so if they are close enough
+	// to the chord, so is the curve
+	if depth >= 24 || (segmentClosest(c.P0, c.P3, c.P1).To(c.P1).Len() <= tolerance &&
+		segmentClosest(c.P0, c.P3, c.P2).To(c.P2).Len() <= tolerance) {
+		return append(points, c.P3)
+	}
+	a, b := c.Split(0.5)
+	points = a.flatten(points, tolerance, depth+1)
+	return b.flatten(points, tolerance, depth+1)
+}
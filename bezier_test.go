@@ -0,0 +1,121 @@
+package pixel_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	arch = pixel.CubicBezier{P0: pixel.V(0, 0), P1: pixel.V(0, 100), P2: pixel.V(100, 100), P3: pixel.V(100, 0)}
+	hump = pixel.QuadBezier{P0: pixel.V(0, 0), P1: pixel.V(50, 100), P2: pixel.V(100, 0)}
+)
+
+func TestCubicBezier_At(t *testing.T) {
+	assert.Equal(t, arch.P0, arch.At(0))
+	assert.Equal(t, arch.P3, arch.At(1))
+	assertVecInDelta(t, pixel.V(50, 75), arch.At(0.5), 1e-9)
+
+	assert.Equal(t, hump.P0, hump.At(0))
+	assert.Equal(t, hump.P2, hump.At(1))
+	assertVecInDelta(t, pixel.V(50, 50), hump.At(0.5), 1e-9)
+
+	// degree elevation keeps the curve intact
+	for _, tt := range []float64{0, 0.1, 0.3, 0.5, 0.77, 1} {
+		assertVecInDelta(t, hump.At(tt), hump.Cubic().At(tt), 1e-9)
+	}
+}
+
+func TestCubicBezier_Tangent(t *testing.T) {
+	assertVecInDelta(t, pixel.V(0, 1), arch.Tangent(0), 1e-9)
+	assertVecInDelta(t, pixel.V(1, 0), arch.Tangent(0.5), 1e-9)
+	assertVecInDelta(t, pixel.V(0, -1), arch.Tangent(1), 1e-9)
+	assertVecInDelta(t, pixel.V(1, 0), hump.Tangent(0.5), 1e-9)
+	assertVecInDelta(t, pixel.V(100, 0), hump.Derivative(0.5), 1e-9)
+
+	// derivative is zero at the start, because P0 and P1 coincide
+	degenerate := pixel.CubicBezier{P0: pixel.V(0, 0), P1: pixel.V(0, 0), P2: pixel.V(10, 10), P3: pixel.V(20, 0)}
+	assertVecInDelta(t, pixel.V(1, 1).Unit(), degenerate.Tangent(0), 1e-9)
+}
+
+func TestCubicBezier_Split(t *testing.T) {
+	a, b := arch.Split(0.3)
+	assert.Equal(t, arch.P0, a.P0)
+	assert.Equal(t, arch.P3, b.P3)
+	assertVecInDelta(t, arch.At(0.3), a.P3, 1e-9)
+	assertVecInDelta(t, arch.At(0.15), a.At(0.5), 1e-9)
+	assertVecInDelta(t, arch.At(0.65), b.At(0.5), 1e-9)
+
+	qa, qb := hump.Split(0.5)
+	assertVecInDelta(t, hump.At(0.25), qa.At(0.5), 1e-9)
+	assertVecInDelta(t, hump.At(0.75), qb.At(0.5), 1e-9)
+}
+
+func TestCubicBezier_Len(t *testing.T) {
+	line := pixel.CubicBezier{P0: pixel.V(0, 0), P1: pixel.V(1, 1), P2: pixel.V(2, 2), P3: pixel.V(3, 3)}
+	assert.InDelta(t, 3*math.Sqrt2, line.Len(), 1e-9)
+
+	// a quarter of a unit circle approximated by a cubic Bezier curve
+	k := 4.0 / 3 * (math.Sqrt2 - 1)
+	quarter := pixel.CubicBezier{P0: pixel.V(1, 0), P1: pixel.V(1, k), P2: pixel.V(k, 1), P3: pixel.V(0, 1)}
+	assert.InDelta(t, math.Pi/2, quarter.Len(), 1e-3)
+
+	var polyline float64
+	points := arch.Flatten(1e-4)
+	for i := 1; i < len(points); i++ {
+		polyline += points[i-1].To(points[i]).Len()
+	}
+	assert.InDelta(t, polyline, arch.Len(), 1e-3)
+
+	// the length of a parabola is known analytically
+	parabola := 50*math.Sqrt(5) + 25*math.Log(2+math.Sqrt(5))
+	assert.InDelta(t, parabola, hump.Len(), 1e-6)
+}
+
+func TestCubicBezier_Bounds(t *testing.T) {
+	assert.Equal(t, pixel.R(0, 0, 100, 75), arch.Bounds())
+	assertVecInDelta(t, pixel.V(0, 0), hump.Bounds().Min, 1e-9)
+	assertVecInDelta(t, pixel.V(100, 50), hump.Bounds().Max, 1e-9)
+
+	s := pixel.CubicBezier{P0: pixel.V(0, 0), P1: pixel.V(100, 100), P2: pixel.V(-100, 100), P3: pixel.V(0, 0)}
+	b := s.Bounds()
+	for i := 0; i <= 100; i++ {
+		assert.True(t, b.Resized(b.Center(), b.Size().Add(pixel.V(1e-9, 1e-9))).Contains(s.At(float64(i)/100)))
+	}
+	assert.InDelta(t, 75, b.Max.Y, 1e-9)
+}
+
+func TestCubicBezier_Closest(t *testing.T) {
+	p, tt := arch.Closest(pixel.V(50, 200))
+	assertVecInDelta(t, pixel.V(50, 75), p, 1e-6)
+	assert.InDelta(t, 0.5, tt, 1e-6)
+
+	p, tt = arch.Closest(pixel.V(-10, -10))
+	assert.Equal(t, arch.P0, p)
+	assert.Equal(t, 0.0, tt)
+
+	p, _ = hump.Closest(pixel.V(50, 40))
+	assertVecInDelta(t, pixel.V(50, 50), p, 1e-6)
+}
+
+func TestCubicBezier_Flatten(t *testing.T) {
+	for _, tolerance := range []float64{10, 1, 0.1, 0.01} {
+		points := arch.Flatten(tolerance)
+		assert.Equal(t, arch.P0, points[0])
+		assert.Equal(t, arch.P3, points[len(points)-1])
+
+		for i := 0; i <= 1000; i++ {
+			p := arch.At(float64(i) / 1000)
+			dist := math.Inf(+1)
+			for j := 1; j < len(points); j++ {
+				dist = math.Min(dist, pixel.L(points[j-1], points[j]).Closest(p).To(p).Len())
+			}
+			assert.LessOrEqual(t, dist, tolerance+1e-9)
+		}
+	}
+
+	assert.Len(t, pixel.QuadBezier{P0: pixel.V(0, 0), P1: pixel.V(1, 0), P2: pixel.V(2, 0)}.Flatten(0.1), 2)
+	assert.Panics(t, func() { arch.Flatten(0) })
+}
@@ -48,6 +48,15 @@ func (c Circle) Area() float64 {
 	return math.Pi * math.Pow(c.Radius, 2)
 }
 
+// Bounds returns the smallest Rect which contains the whole Circle. The Rect is normalized.
+func (c Circle) Bounds() Rect {
+	r := math.Abs(c.Radius)
+	return Rect{
+		Min: c.Center.Sub(V(r, r)),
+		Max: c.Center.Add(V(r, r)),
+	}
+}
+
 // Moved returns the Circle moved by the given vector delta.
 func (c Circle) Moved(delta Vec) Circle {
 	return Circle{
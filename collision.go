@@ -0,0 +1,403 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+// Shape is a 2D geometric shape. Rect, Circle, Line and Polygon are Shapes.
+type Shape interface {
+	// Bounds returns the smallest normalized Rect which contains the whole Shape.
+	Bounds() Rect
+}
+
+var (
+	_ Shape = Rect{}
+	_ Shape = Circle{}
+	_ Shape = Line{}
+	_ Shape = Polygon{}
+)
+
+// Manifold describes the contact between two Shapes, A and B, as returned by Collide.
+type Manifold struct {
+	// Colliding is whether the two Shapes overlap. Shapes which only touch are not colliding.
+	// If the Shapes aren't colliding, all other fields are zero.
+	Colliding bool
+
+	// Normal is the unit vector along which the Shapes are penetrating the least. It points from
+	// A to B.
+	Normal Vec
+
+	// Depth is the length of the penetration along the Normal.
+	Depth float64
+
+	// Points are the contact points of the two Shapes. There are one or two of them.
+	Points []Vec
+}
+
+// MTV returns the minimum translation vector, which is the shortest vector such that moving B by
+// it (or moving A by its opposite) makes the two Shapes stop colliding.
+func (m Manifold) MTV() Vec {
+	return m.Normal.Scaled(m.Depth)
+}
+
+// Collide tests two Shapes for a collision using the separating axis theorem and returns the
+// contact Manifold.
+//
+// Any pair of Rect, Circle, Line and Polygon is supported. Polygons must be convex, the result
+// is undefined for concave Polygons. Rects don't have to be normalized, Circles with negative
+// radius are treated as their normalized variants. Collide panics if passed any other Shape.
+//
+//   m := pixel.Collide(player, wall)
+//   if m.Colliding {
+//       player = player.Moved(m.MTV().Scaled(-1))
+//   }
+func Collide(a, b Shape) Manifold {
+	ca, cb := toConvex(a), toConvex(b)
+
+	var (
+		best    = math.Inf(+1)
+		normal  Vec
+		checked bool
+	)
+	for _, axis := range satAxes(ca, cb) {
+		minA, maxA := ca.project(axis)
+		minB, maxB := cb.project(axis)
+
+		// overlap when moving B in the direction of the axis, or in the opposite direction
+		forward, backward := maxA-minB, maxB-minA
+		if forward <= 0 || backward <= 0 {
+			return Manifold{}
+		}
+		checked = true
+		if forward < best {
+			best, normal = forward, axis
+		}
+		if backward < best {
+			best, normal = backward, axis.Scaled(-1)
+		}
+	}
+	if !checked {
+		return Manifold{}
+	}
+
+	return Manifold{
+		Colliding: true,
+		Normal:    normal,
+		Depth:     best,
+		Points:    contactPoints(ca, cb, normal),
+	}
+}
+
+// convex is the common representation of all Shapes in Collide: a convex polygon (possibly
+// degenerated into a segment or a single point) inflated by a radius.
+type convex struct {
+	vertices []Vec
+	radius   float64
+}
+
+func toConvex(s Shape) convex {
+	switch s := s.(type) {
+	case Rect:
+		v := s.Norm().Vertices()
+		return convex{vertices: v[:]}
+	case Circle:
+		s = s.Norm()
+		return convex{vertices: []Vec{s.Center}, radius: s.Radius}
+	case Line:
+		return convex{vertices: []Vec{s.A, s.B}}
+	case Polygon:
+		return convex{vertices: s}
+	default:
+		panic(fmt.Errorf("Collide: unsupported Shape %T", s))
+	}
+}
+
+func (c convex) project(axis Vec) (min, max float64) {
+	min, max = math.Inf(+1), math.Inf(-1)
+	for _, v := range c.vertices {
+		d := v.Dot(axis)
+		min, max = math.Min(min, d), math.Max(max, d)
+	}
+	return min - c.radius, max + c.radius
+}
+
+// support returns the index of the vertex furthest in the direction of the axis.
+func (c convex) support(axis Vec) int {
+	best, idx := math.Inf(-1), 0
+	for i, v := range c.vertices {
+		if d := v.Dot(axis); d > best {
+			best, idx = d, i
+		}
+	}
+	return idx
+}
+
+// satAxes returns all axes which need to be checked for separation of the two convex shapes.
+func satAxes(a, b convex) []Vec {
+	var axes []Vec
+	for _, c := range [...]convex{a, b} {
+		for i := range c.vertices {
+			edge := c.vertices[i].To(c.vertices[(i+1)%len(c.vertices)])
+			if edge != ZV {
+				axes = append(axes, edge.Normal().Unit())
+			}
+		}
+	}
+
+	// round shapes are closest to the other shape along the line to its closest vertex
+	for _, pair := range [...][2]convex{{a, b}, {b, a}} {
+		round, other := pair[0], pair[1]
+		if len(round.vertices) != 1 {
+			continue
+		}
+		center := round.vertices[0]
+		closest := other.vertices[0]
+		for _, v := range other.vertices[1:] {
+			if center.To(v).SqLen() < center.To(closest).SqLen() {
+				closest = v
+			}
+		}
+		if axis := center.To(closest); axis != ZV {
+			axes = append(axes, axis.Unit())
+		}
+	}
+
+	// two concentric circles have no natural axis at all, any axis works
+	if len(axes) == 0 {
+		axes = append(axes, V(0, 1))
+	}
+	return axes
+}
+
+// contactPoints finds the points where the two colliding convex shapes touch. The normal points
+// from a to b.
+func contactPoints(a, b convex, normal Vec) []Vec {
+	if len(a.vertices) == 1 {
+		return []Vec{a.vertices[0].Add(normal.Scaled(a.radius))}
+	}
+	if len(b.vertices) == 1 {
+		return []Vec{b.vertices[0].Sub(normal.Scaled(b.radius))}
+	}
+
+	// both shapes are polygons or segments, clip the incident edge by the reference edge
+	ea := bestEdge(a, normal)
+	eb := bestEdge(b, normal.Scaled(-1))
+
+	ref, inc, refNormal := ea, eb, normal
+	if math.Abs(eb.dir().Unit().Dot(normal)) < math.Abs(ea.dir().Unit().Dot(normal)) {
+		ref, inc, refNormal = eb, ea, normal.Scaled(-1)
+	}
+
+	u := ref.dir().Unit()
+	points := clipSegment(inc.a, inc.b, u, u.Dot(ref.a))
+	if len(points) < 2 {
+		return []Vec{b.vertices[b.support(normal.Scaled(-1))]}
+	}
+	points = clipSegment(points[0], points[1], u.Scaled(-1), -u.Dot(ref.b))
+	if len(points) < 2 {
+		return []Vec{b.vertices[b.support(normal.Scaled(-1))]}
+	}
+
+	// orient the normal of the reference edge towards the incident shape and only keep the points
+	// which penetrate past the reference edge
+	n := ref.dir().Normal().Unit()
+	if n.Dot(refNormal) < 0 {
+		n = n.Scaled(-1)
+	}
+	max := n.Dot(ref.max)
+	var contacts []Vec
+	for _, p := range points {
+		if n.Dot(p) <= max+1e-9 {
+			contacts = append(contacts, p)
+		}
+	}
+	if len(contacts) == 0 {
+		return []Vec{b.vertices[b.support(normal.Scaled(-1))]}
+	}
+	if len(contacts) == 2 && contacts[0].Eq(contacts[1]) {
+		contacts = contacts[:1]
+	}
+	return contacts
+}
+
+// satEdge is an edge of a convex shape from a to b, which contains the vertex max.
+type satEdge struct {
+	a, b, max Vec
+}
+
+func (e satEdge) dir() Vec {
+	return e.a.To(e.b)
+}
+
+// bestEdge returns the edge of the convex shape which is the most perpendicular to the direction
+// and contains the vertex furthest in the direction.
+func bestEdge(c convex, dir Vec) satEdge {
+	n := len(c.vertices)
+	i := c.support(dir)
+	v := c.vertices[i]
+	prev := c.vertices[(i-1+n)%n]
+	next := c.vertices[(i+1)%n]
+
+	left, right := prev.To(v), v.To(next)
+	if math.Abs(right.Unit().Dot(dir)) <= math.Abs(left.Unit().Dot(dir)) {
+		return satEdge{a: v, b: next, max: v}
+	}
+	return satEdge{a: prev, b: v, max: v}
+}
+
+// clipSegment clips the segment between a and b, keeping only the part whose projection onto the
+// direction is at least offset.
+func clipSegment(a, b, dir Vec, offset float64) []Vec {
+	da, db := dir.Dot(a)-offset, dir.Dot(b)-offset
+	var points []Vec
+	if da >= 0 {
+		points = append(points, a)
+	}
+	if db >= 0 {
+		points = append(points, b)
+	}
+	if da*db < 0 {
+		points = append(points, Lerp(a, b, da/(da-db)))
+	}
+	return points
+}
+
+// Distance returns the distance between two Shapes along with the points of each Shape closest
+// to the other one, using the GJK algorithm.
+//
+// Shapes are supported the same way as in Collide, including the requirement for Polygons to be
+// convex. If the Shapes overlap, the distance is zero and both points are the same point lying
+// in both Shapes. If either of them is an empty Polygon, the distance is +Inf and both points are
+// ZV.
+//
+//   dist, onPlayer, onWall := pixel.Distance(player, wall)
+//   if dist < 5 {
+//       // too close, push the player away in the direction of onWall.To(onPlayer)
+//   }
+func Distance(a, b Shape) (dist float64, closestA, closestB Vec) {
+	ca, cb := toConvex(a), toConvex(b)
+	if len(ca.vertices) == 0 || len(cb.vertices) == 0 {
+		return math.Inf(+1), ZV, ZV
+	}
+	pa, pb, overlap := gjkClosest(ca.vertices, cb.vertices)
+	if overlap {
+		return 0, pa, pa
+	}
+
+	// the radii of round shapes only inflate their cores
+	d := pa.To(pb).Len()
+	if d <= ca.radius+cb.radius {
+		if d == 0 {
+			return 0, pa, pa
+		}
+		common := pa.Add(pa.To(pb).Scaled(math.Min(ca.radius, d) / d))
+		return 0, common, common
+	}
+	dir := pa.To(pb).Scaled(1 / d)
+	return d - ca.radius - cb.radius, pa.Add(dir.Scaled(ca.radius)), pb.Sub(dir.Scaled(cb.radius))
+}
+
+// gjkVertex is a vertex of the Minkowski difference of two convex shapes, remembering the vertices
+// of the shapes it was made of.
+type gjkVertex struct {
+	a, b, v Vec
+}
+
+// gjkClosest returns the closest points of the convex hulls of two sets of points, or a point
+// inside both of them and true if the hulls overlap.
+func gjkClosest(a, b []Vec) (closestA, closestB Vec, overlap bool) {
+	support := func(dir Vec) gjkVertex {
+		va := a[convex{vertices: a}.support(dir)]
+		vb := b[convex{vertices: b}.support(dir.Scaled(-1))]
+		return gjkVertex{a: va, b: vb, v: va.Sub(vb)}
+	}
+
+	simplex := []gjkVertex{support(a[0].To(b[0]))}
+	var weights []float64
+	for i := 0; i < 64; i++ {
+		var closest Vec
+		simplex, weights, closest = closestOnSimplex(simplex)
+		// the origin may end up just next to the simplex due to rounding errors, so compare its
+		// distance to the size of the simplex
+		var size float64
+		for _, s := range simplex {
+			size = math.Max(size, s.v.SqLen())
+		}
+		if closest.SqLen() <= 1e-20*size {
+			overlap = true
+			break
+		}
+		w := support(closest.Scaled(-1))
+		// stop when the new vertex isn't getting any closer to the origin
+		if closest.Dot(closest)-closest.Dot(w.v) <= 1e-12*closest.Dot(closest) {
+			break
+		}
+		duplicate := false
+		for _, s := range simplex {
+			if s.v == w.v {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			break
+		}
+		simplex = append(simplex, w)
+	}
+
+	var pa, pb Vec
+	for i, s := range simplex {
+		pa = pa.Add(s.a.Scaled(weights[i]))
+		pb = pb.Add(s.b.Scaled(weights[i]))
+	}
+	return pa, pb, overlap
+}
+
+// closestOnSimplex finds the point of the simplex (a point, a segment or a triangle) closest to
+// the origin. It returns the smallest part of the simplex containing the point along with the
+// barycentric weights of the point.
+func closestOnSimplex(simplex []gjkVertex) ([]gjkVertex, []float64, Vec) {
+	switch len(simplex) {
+	case 1:
+		return simplex, []float64{1}, simplex[0].v
+	case 2:
+		a, b := simplex[0].v, simplex[1].v
+		ab := a.To(b)
+		if ab.SqLen() == 0 {
+			return simplex[:1], []float64{1}, a
+		}
+		t := Clamp(-a.Dot(ab)/ab.SqLen(), 0, 1)
+		switch t {
+		case 0:
+			return simplex[:1], []float64{1}, a
+		case 1:
+			return simplex[1:], []float64{1}, b
+		}
+		return simplex, []float64{1 - t, t}, Lerp(a, b, t)
+	}
+
+	// triangle: the origin is either inside, or the closest point lies on one of the edges
+	a, b, c := simplex[0].v, simplex[1].v, simplex[2].v
+	area := a.To(b).Cross(a.To(c))
+	if area != 0 {
+		wa := b.Cross(c) / area
+		wb := c.Cross(a) / area
+		wc := a.Cross(b) / area
+		if wa >= 0 && wb >= 0 && wc >= 0 {
+			return simplex, []float64{wa, wb, wc}, ZV
+		}
+	}
+	var (
+		best        []gjkVertex
+		bestWeights []float64
+		bestPoint   Vec
+		bestDist    = math.Inf(+1)
+	)
+	for _, edge := range [...][2]int{{0, 1}, {1, 2}, {2, 0}} {
+		s, w, p := closestOnSimplex([]gjkVertex{simplex[edge[0]], simplex[edge[1]]})
+		if d := p.SqLen(); d < bestDist {
+			best, bestWeights, bestPoint, bestDist = s, w, p, d
+		}
+	}
+	return best, bestWeights, bestPoint
+}
@@ -0,0 +1,206 @@
+package pixel_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertVecInDelta(t *testing.T, expected, actual pixel.Vec, delta float64, msgAndArgs ...interface{}) {
+	t.Helper()
+	assert.InDelta(t, expected.X, actual.X, delta, msgAndArgs...)
+	assert.InDelta(t, expected.Y, actual.Y, delta, msgAndArgs...)
+}
+
+func TestCollide(t *testing.T) {
+	diamond := pixel.Polygon{pixel.V(0, -2), pixel.V(2, 0), pixel.V(0, 2), pixel.V(-2, 0)}
+
+	tests := []struct {
+		name      string
+		a, b      pixel.Shape
+		colliding bool
+		mtv       pixel.Vec
+	}{
+		{"rect rect", pixel.R(0, 0, 10, 10), pixel.R(8, 2, 20, 8), true, pixel.V(2, 0)},
+		{"rect rect vertical", pixel.R(0, 0, 10, 10), pixel.R(2, -5, 8, 1), true, pixel.V(0, -1)},
+		{"rect rect touching", pixel.R(0, 0, 10, 10), pixel.R(10, 0, 20, 10), false, pixel.ZV},
+		{"rect rect apart", pixel.R(0, 0, 10, 10), pixel.R(11, 0, 20, 10), false, pixel.ZV},
+		{"rect rect not normalized", pixel.R(10, 10, 0, 0), pixel.R(8, 2, 20, 8), true, pixel.V(2, 0)},
+		{"circle circle", pixel.C(pixel.ZV, 5), pixel.C(pixel.V(8, 0), 5), true, pixel.V(2, 0)},
+		{"circle circle apart", pixel.C(pixel.ZV, 5), pixel.C(pixel.V(0, 11), 5), false, pixel.ZV},
+		{"circle circle concentric", pixel.C(pixel.ZV, 5), pixel.C(pixel.ZV, 2), true, pixel.V(0, 7)},
+		{"rect circle side", pixel.R(0, 0, 10, 10), pixel.C(pixel.V(5, 12), 3), true, pixel.V(0, 1)},
+		{"rect circle corner", pixel.R(0, 0, 10, 10), pixel.C(pixel.V(13, 14), 6), true, pixel.V(0.6, 0.8)},
+		{"rect circle corner apart", pixel.R(0, 0, 10, 10), pixel.C(pixel.V(13, 14), 4.9), false, pixel.ZV},
+		{"circle rect", pixel.C(pixel.V(5, 12), 3), pixel.R(0, 0, 10, 10), true, pixel.V(0, -1)},
+		{"line rect", pixel.L(pixel.V(-5, 1), pixel.V(5, 1)), pixel.R(0, 0, 10, 10), true, pixel.V(0, 1)},
+		{"line line crossing", pixel.L(pixel.V(0, 0), pixel.V(10, 0)), pixel.L(pixel.V(5, -1), pixel.V(5, 3)), true, pixel.V(0, 1)},
+		{"line line apart", pixel.L(pixel.V(0, 0), pixel.V(10, 0)), pixel.L(pixel.V(5, 1), pixel.V(5, 3)), false, pixel.ZV},
+		{"line circle", pixel.L(pixel.V(-10, 0), pixel.V(10, 0)), pixel.C(pixel.V(0, 2), 3), true, pixel.V(0, 1)},
+		{"polygon rect", diamond, pixel.R(1, -1, 5, 1), true, pixel.V(1, 0)},
+		{"polygon circle", diamond, pixel.C(pixel.V(2, 2), math.Sqrt2*1.5), true, pixel.V(0.5, 0.5)},
+		{"polygon polygon apart", diamond, diamond.Moved(pixel.V(4.1, 0)), false, pixel.ZV},
+		{"clockwise polygon", diamond.Reversed(), pixel.R(1, -1, 5, 1), true, pixel.V(1, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := pixel.Collide(tt.a, tt.b)
+			assert.Equal(t, tt.colliding, m.Colliding)
+			assertVecInDelta(t, tt.mtv, m.MTV(), 1e-9)
+			if !tt.colliding {
+				assert.Empty(t, m.Points)
+				return
+			}
+			assert.InDelta(t, 1, m.Normal.Len(), 1e-9)
+			assert.NotEmpty(t, m.Points)
+			assert.LessOrEqual(t, len(m.Points), 2)
+		})
+	}
+}
+
+func TestCollide_Points(t *testing.T) {
+	m := pixel.Collide(pixel.R(0, 0, 10, 10), pixel.R(8, 2, 20, 8))
+	if assert.Len(t, m.Points, 2) {
+		assertVecInDelta(t, pixel.V(8, 2), m.Points[0], 1e-9)
+		assertVecInDelta(t, pixel.V(8, 8), m.Points[1], 1e-9)
+	}
+
+	m = pixel.Collide(pixel.R(0, 0, 10, 10), pixel.C(pixel.V(5, 12), 3))
+	if assert.Len(t, m.Points, 1) {
+		assertVecInDelta(t, pixel.V(5, 9), m.Points[0], 1e-9)
+	}
+
+	// a rotated square poking into a rect with a single corner
+	diamond := pixel.Polygon{pixel.V(0, -2), pixel.V(2, 0), pixel.V(0, 2), pixel.V(-2, 0)}
+	m = pixel.Collide(diamond, pixel.R(1, -5, 5, 5))
+	if assert.Len(t, m.Points, 1) {
+		assertVecInDelta(t, pixel.V(2, 0), m.Points[0], 1e-9)
+	}
+}
+
+func TestCollide_Resolves(t *testing.T) {
+	shapes := []pixel.Shape{
+		pixel.R(0, 0, 10, 10),
+		pixel.C(pixel.V(5, 5), 4),
+		pixel.L(pixel.V(0, 0), pixel.V(10, 7)),
+		pixel.Polygon{pixel.V(0, 0), pixel.V(8, 1), pixel.V(6, 9)},
+	}
+	moved := func(s pixel.Shape, delta pixel.Vec) pixel.Shape {
+		switch s := s.(type) {
+		case pixel.Rect:
+			return s.Moved(delta)
+		case pixel.Circle:
+			return s.Moved(delta)
+		case pixel.Line:
+			return s.Moved(delta)
+		case pixel.Polygon:
+			return s.Moved(delta)
+		}
+		panic("unreachable")
+	}
+
+	for _, a := range shapes {
+		for _, b := range shapes {
+			if _, ok := a.(pixel.Line); ok {
+				if _, ok := b.(pixel.Line); ok {
+					// parallel lines never collide
+					continue
+				}
+			}
+			b := moved(b, pixel.V(3, 2))
+			m := pixel.Collide(a, b)
+			if !assert.True(t, m.Colliding, "%v and %v", a, b) {
+				continue
+			}
+			resolved := moved(b, m.MTV().Add(m.Normal.Scaled(1e-6)))
+			assert.False(t, pixel.Collide(a, resolved).Colliding, "%v and %v", a, resolved)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	diamond := pixel.Polygon{pixel.V(0, -2), pixel.V(2, 0), pixel.V(0, 2), pixel.V(-2, 0)}
+
+	tests := []struct {
+		name     string
+		a, b     pixel.Shape
+		dist     float64
+		closestA pixel.Vec
+		closestB pixel.Vec
+	}{
+		{"rect rect", pixel.R(0, 0, 10, 10), pixel.R(13, 14, 20, 20), 5, pixel.V(10, 10), pixel.V(13, 14)},
+		{"circle circle", pixel.C(pixel.ZV, 1), pixel.C(pixel.V(5, 0), 2), 2, pixel.V(1, 0), pixel.V(3, 0)},
+		{"rect circle", pixel.R(0, 0, 10, 10), pixel.C(pixel.V(13, 14), 1), 4, pixel.V(10, 10), pixel.V(12.4, 13.2)},
+		{"line diamond", pixel.L(pixel.V(4, -5), pixel.V(4, 5)), diamond, 2, pixel.V(4, 0), pixel.V(2, 0)},
+		{"overlapping rects", pixel.R(0, 0, 10, 10), pixel.R(5, 5, 20, 20), 0, pixel.ZV, pixel.ZV},
+		{"overlapping circles", pixel.C(pixel.ZV, 2), pixel.C(pixel.V(3, 0), 2), 0, pixel.V(2, 0), pixel.V(2, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist, a, b := pixel.Distance(tt.a, tt.b)
+			assert.InDelta(t, tt.dist, dist, 1e-9)
+			if tt.dist == 0 && tt.closestA == pixel.ZV {
+				// any common point is fine
+				assert.Equal(t, a, b)
+				return
+			}
+			assertVecInDelta(t, tt.closestA, a, 1e-9)
+			assertVecInDelta(t, tt.closestB, b, 1e-9)
+		})
+	}
+
+	// the closest points of parallel edges aren't unique
+	dist, a, b := pixel.Distance(pixel.R(0, 0, 10, 10), pixel.R(2, 12, 4, 20))
+	assert.InDelta(t, 2, dist, 1e-9)
+	assertVecInDelta(t, pixel.V(0, 2), a.To(b), 1e-9)
+	assert.True(t, 2-1e-9 <= a.X && a.X <= 4+1e-9)
+
+	// empty Polygons are infinitely far from everything
+	dist, a, b = pixel.Distance(pixel.Polygon{}, pixel.R(0, 0, 1, 1))
+	assert.True(t, math.IsInf(dist, +1))
+	assert.Equal(t, pixel.ZV, a)
+	assert.Equal(t, pixel.ZV, b)
+	dist, _, _ = pixel.Distance(pixel.C(pixel.ZV, 1), pixel.Polygon{})
+	assert.True(t, math.IsInf(dist, +1))
+}
+
+func TestDistance_Polygons(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	randomConvex := func() pixel.Polygon {
+		points := make([]pixel.Vec, 3+rnd.Intn(6))
+		center := pixel.V(rnd.Float64()*30, rnd.Float64()*30)
+		for j := range points {
+			points[j] = center.Add(pixel.V(rnd.NormFloat64()*4, rnd.NormFloat64()*4))
+		}
+		return pixel.ConvexHull(points)
+	}
+
+	for i := 0; i < 200; i++ {
+		a, b := randomConvex(), randomConvex()
+		dist, pa, pb := pixel.Distance(a, b)
+		if pixel.Collide(a, b).Colliding {
+			assert.Equal(t, 0.0, dist)
+			continue
+		}
+
+		// the closest points of separate convex polygons lie on their edges
+		expected := math.Inf(+1)
+		for _, ea := range a.Edges() {
+			for _, eb := range b.Edges() {
+				for _, d := range []float64{
+					ea.Closest(eb.A).To(eb.A).Len(), ea.Closest(eb.B).To(eb.B).Len(),
+					eb.Closest(ea.A).To(ea.A).Len(), eb.Closest(ea.B).To(ea.B).Len(),
+				} {
+					expected = math.Min(expected, d)
+				}
+			}
+		}
+		assert.InDelta(t, expected, dist, 1e-6)
+		assert.InDelta(t, dist, pa.To(pb).Len(), 1e-6)
+		assert.True(t, onBorder(a, pa))
+		assert.True(t, onBorder(b, pb))
+	}
+}
@@ -0,0 +1,247 @@
+package pixel
+
+import "math"
+
+// unpremultiplied returns the straight (not alpha-premultiplied) RGB components of the color.
+func (c RGBA) unpremultiplied() (r, g, b float64) {
+	if c.A == 0 {
+		return 0, 0, 0
+	}
+	return c.R / c.A, c.G / c.A, c.B / c.A
+}
+
+// withAlpha returns the color with the straight RGB components premultiplied by the alpha.
+func withAlpha(r, g, b, a float64) RGBA {
+	return RGBA{r * a, g * a, b * a, a}
+}
+
+// HSV returns a fully opaque RGBA color with the given hue, saturation and value. The hue is in
+// degrees, the saturation and the value are within range [0, 1].
+//
+//   pixel.HSV(120, 1, 1) // green
+//   pixel.HSV(0, 0, 0.5) // gray
+func HSV(h, s, v float64) RGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	// the channels are the value minus the chroma scaled by the distance from their hue
+	channel := func(n float64) float64 {
+		k := math.Mod(n+h/60, 6)
+		return v - v*s*math.Max(0, math.Min(math.Min(k, 4-k), 1))
+	}
+	return RGB(channel(5), channel(3), channel(1))
+}
+
+// HSV returns the hue, saturation and value of the color. The hue is in degrees within range
+// [0, 360), the saturation and the value are within range [0, 1]. Colors with no saturation have
+// zero hue. The alpha of the color is ignored.
+func (c RGBA) HSV() (h, s, v float64) {
+	r, g, b := c.unpremultiplied()
+	max := math.Max(r, math.Max(g, b))
+	chroma := max - math.Min(r, math.Min(g, b))
+	if max > 0 {
+		s = chroma / max
+	}
+	return hue(r, g, b, max, chroma), s, max
+}
+
+// hue returns the hue in degrees of the straight RGB color with the given maximal component and
+// chroma.
+func hue(r, g, b, max, chroma float64) float64 {
+	var h float64
+	switch {
+	case chroma == 0:
+		return 0
+	case max == r:
+		h = (g - b) / chroma
+	case max == g:
+		h = (b-r)/chroma + 2
+	default:
+		h = (r-g)/chroma + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// HSL returns a fully opaque RGBA color with the given hue, saturation and lightness. The hue is
+// in degrees, the saturation and the lightness are within range [0, 1].
+//
+//   pixel.HSL(240, 1, 0.5)  // blue
+//   pixel.HSL(240, 1, 0.75) // light blue
+func HSL(h, s, l float64) RGBA {
+	v := l + s*math.Min(l, 1-l)
+	var sv float64
+	if v > 0 {
+		sv = 2 * (1 - l/v)
+	}
+	return HSV(h, sv, v)
+}
+
+// HSL returns the hue, saturation and lightness of the color. The hue is in degrees within range
+// [0, 360), the saturation and the lightness are within range [0, 1]. Colors with no saturation
+// have zero hue. The alpha of the color is ignored.
+func (c RGBA) HSL() (h, s, l float64) {
+	r, g, b := c.unpremultiplied()
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	chroma := max - min
+	l = (max + min) / 2
+	if l > 0 && l < 1 {
+		s = (max - l) / math.Min(l, 1-l)
+	}
+	return hue(r, g, b, max, chroma), s, l
+}
+
+// srgbToLinear converts a gamma-encoded sRGB component to linear light.
+func srgbToLinear(x float64) float64 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return math.Pow((x+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear light component to gamma-encoded sRGB.
+func linearToSRGB(x float64) float64 {
+	if x <= 0.0031308 {
+		return x * 12.92
+	}
+	return 1.055*math.Pow(x, 1/2.4) - 0.055
+}
+
+// LinearRGB returns a fully opaque RGBA color with the given components in linear light, that is,
+// before the sRGB gamma encoding. Light mixes linearly in this space, so it's the right space for
+// blending and lighting.
+func LinearRGB(r, g, b float64) RGBA {
+	return RGB(linearToSRGB(r), linearToSRGB(g), linearToSRGB(b))
+}
+
+// LinearRGB returns the components of the color in linear light, that is, with the sRGB gamma
+// encoding removed. The alpha of the color is ignored.
+func (c RGBA) LinearRGB() (r, g, b float64) {
+	r, g, b = c.unpremultiplied()
+	return srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+}
+
+// D65 white point of sRGB in the CIE XYZ color space.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// Lab returns a fully opaque RGBA color with the given CIE L*a*b* coordinates (under the D65
+// white point). The lightness l is within range [0, 100], a and b are roughly within range
+// [-128, 127]. Colors outside of the sRGB gamut have components outside of range [0, 1].
+func Lab(l, a, b float64) RGBA {
+	finv := func(t float64) float64 {
+		if t > 6.0/29 {
+			return t * t * t
+		}
+		return 3 * (6.0 / 29) * (6.0 / 29) * (t - 4.0/29)
+	}
+	fy := (l + 16) / 116
+	x := whiteX * finv(fy+a/500)
+	y := whiteY * finv(fy)
+	z := whiteZ * finv(fy-b/200)
+	return LinearRGB(
+		3.2404542*x-1.5371385*y-0.4985314*z,
+		-0.9692660*x+1.8760108*y+0.0415560*z,
+		0.0556434*x-0.2040259*y+1.0572252*z,
+	)
+}
+
+// Lab returns the CIE L*a*b* coordinates of the color (under the D65 white point). The lightness
+// l is within range [0, 100]. The alpha of the color is ignored.
+func (c RGBA) Lab() (l, a, b float64) {
+	lr, lg, lb := c.LinearRGB()
+	x := 0.4124564*lr + 0.3575761*lg + 0.1804375*lb
+	y := 0.2126729*lr + 0.7151522*lg + 0.0721750*lb
+	z := 0.0193339*lr + 0.1191920*lg + 0.9503041*lb
+	f := func(t float64) float64 {
+		if t > (6.0/29)*(6.0/29)*(6.0/29) {
+			return math.Cbrt(t)
+		}
+		return t/(3*(6.0/29)*(6.0/29)) + 4.0/29
+	}
+	fx, fy, fz := f(x/whiteX), f(y/whiteY), f(z/whiteZ)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// OKLab returns a fully opaque RGBA color with the given OKLab coordinates. The lightness l is
+// within range [0, 1], a and b are roughly within range [-0.4, 0.4]. Colors outside of the sRGB
+// gamut have components outside of range [0, 1].
+//
+// OKLab is a perceptual color space: the same distances in it look like the same differences in
+// color, which makes it the best space for mixing colors and making gradients.
+func OKLab(l, a, b float64) RGBA {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+	return LinearRGB(
+		4.0767416621*lc-3.3077115913*mc+0.2309699292*sc,
+		-1.2684380046*lc+2.6097574011*mc-0.3413193965*sc,
+		-0.0041960863*lc-0.7034186147*mc+1.7076147010*sc,
+	)
+}
+
+// OKLab returns the OKLab coordinates of the color. The lightness l is within range [0, 1]. The
+// alpha of the color is ignored.
+func (c RGBA) OKLab() (l, a, b float64) {
+	r, g, bl := c.LinearRGB()
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+	return 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc,
+		1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc,
+		0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+}
+
+// Lerp returns the linear interpolation between colors c and d by t, component-wise. This is the
+// fastest, but it tends to produce dull, muddy colors in between.
+func (c RGBA) Lerp(d RGBA, t float64) RGBA {
+	return c.Scaled(1 - t).Add(d.Scaled(t))
+}
+
+// LerpLinear returns the interpolation between colors c and d by t in linear light. It's how two
+// lights fade into each other physically.
+func (c RGBA) LerpLinear(d RGBA, t float64) RGBA {
+	r1, g1, b1 := c.LinearRGB()
+	r2, g2, b2 := d.LinearRGB()
+	mixed := LinearRGB(r1+(r2-r1)*t, g1+(g2-g1)*t, b1+(b2-b1)*t)
+	return withAlpha(mixed.R, mixed.G, mixed.B, c.A+(d.A-c.A)*t)
+}
+
+// LerpHSV returns the interpolation between colors c and d by t in the HSV color space. The hue
+// goes around the shorter way, so the colors in between keep their saturation.
+func (c RGBA) LerpHSV(d RGBA, t float64) RGBA {
+	h1, s1, v1 := c.HSV()
+	h2, s2, v2 := d.HSV()
+	// gray colors have no hue, take it from the other color
+	if s1 == 0 {
+		h1 = h2
+	}
+	if s2 == 0 {
+		h2 = h1
+	}
+	turn := math.Remainder(h2-h1, 360)
+	mixed := HSV(h1+turn*t, s1+(s2-s1)*t, v1+(v2-v1)*t)
+	return withAlpha(mixed.R, mixed.G, mixed.B, c.A+(d.A-c.A)*t)
+}
+
+// LerpOKLab returns the interpolation between colors c and d by t in the perceptual OKLab color
+// space. The colors in between change evenly and stay vivid.
+//
+//   dawn := pixel.RGB(0.9, 0.4, 0.2)
+//   noon := pixel.RGB(1, 1, 0.9)
+//   tint := dawn.LerpOKLab(noon, progress)
+func (c RGBA) LerpOKLab(d RGBA, t float64) RGBA {
+	l1, a1, b1 := c.OKLab()
+	l2, a2, b2 := d.OKLab()
+	mixed := OKLab(l1+(l2-l1)*t, a1+(a2-a1)*t, b1+(b2-b1)*t)
+	return withAlpha(mixed.R, mixed.G, mixed.B, c.A+(d.A-c.A)*t)
+}
@@ -0,0 +1,122 @@
+package pixel_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertRGBAInDelta(t *testing.T, expected, actual pixel.RGBA, delta float64, msgAndArgs ...interface{}) {
+	t.Helper()
+	assert.InDelta(t, expected.R, actual.R, delta, msgAndArgs...)
+	assert.InDelta(t, expected.G, actual.G, delta, msgAndArgs...)
+	assert.InDelta(t, expected.B, actual.B, delta, msgAndArgs...)
+	assert.InDelta(t, expected.A, actual.A, delta, msgAndArgs...)
+}
+
+func TestHSV(t *testing.T) {
+	tests := []struct {
+		h, s, v float64
+		color   pixel.RGBA
+	}{
+		{0, 1, 1, pixel.RGB(1, 0, 0)},
+		{120, 1, 1, pixel.RGB(0, 1, 0)},
+		{240, 1, 1, pixel.RGB(0, 0, 1)},
+		{60, 1, 1, pixel.RGB(1, 1, 0)},
+		{30, 1, 0.5, pixel.RGB(0.5, 0.25, 0)},
+		{0, 0, 0.5, pixel.RGB(0.5, 0.5, 0.5)},
+		{200, 0.5, 0.8, pixel.RGB(0.4, 0.6666666666666667, 0.8)},
+	}
+	for _, tt := range tests {
+		assertRGBAInDelta(t, tt.color, pixel.HSV(tt.h, tt.s, tt.v), 1e-9, "HSV(%v, %v, %v)", tt.h, tt.s, tt.v)
+		h, s, v := tt.color.HSV()
+		assert.InDelta(t, tt.h, h, 1e-9)
+		assert.InDelta(t, tt.s, s, 1e-9)
+		assert.InDelta(t, tt.v, v, 1e-9)
+	}
+
+	// hues wrap around
+	assertRGBAInDelta(t, pixel.HSV(300, 1, 1), pixel.HSV(-60, 1, 1), 1e-9)
+	assertRGBAInDelta(t, pixel.HSV(10, 1, 1), pixel.HSV(370, 1, 1), 1e-9)
+}
+
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		h, s, l float64
+		color   pixel.RGBA
+	}{
+		{0, 1, 0.5, pixel.RGB(1, 0, 0)},
+		{240, 1, 0.75, pixel.RGB(0.5, 0.5, 1)},
+		{120, 1, 0.25, pixel.RGB(0, 0.5, 0)},
+		{0, 0, 1, pixel.RGB(1, 1, 1)},
+		{0, 0, 0, pixel.RGB(0, 0, 0)},
+	}
+	for _, tt := range tests {
+		assertRGBAInDelta(t, tt.color, pixel.HSL(tt.h, tt.s, tt.l), 1e-9, "HSL(%v, %v, %v)", tt.h, tt.s, tt.l)
+		h, s, l := tt.color.HSL()
+		assert.InDelta(t, tt.h, h, 1e-9)
+		assert.InDelta(t, tt.s, s, 1e-9)
+		assert.InDelta(t, tt.l, l, 1e-9)
+	}
+}
+
+func TestRGBA_ColorSpacesRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		c := pixel.RGB(rnd.Float64(), rnd.Float64(), rnd.Float64())
+		assertRGBAInDelta(t, c, pixel.HSV(c.HSV()), 1e-9, "HSV %v", c)
+		assertRGBAInDelta(t, c, pixel.HSL(c.HSL()), 1e-9, "HSL %v", c)
+		assertRGBAInDelta(t, c, pixel.LinearRGB(c.LinearRGB()), 1e-9, "LinearRGB %v", c)
+		assertRGBAInDelta(t, c, pixel.Lab(c.Lab()), 1e-5, "Lab %v", c)
+		assertRGBAInDelta(t, c, pixel.OKLab(c.OKLab()), 1e-5, "OKLab %v", c)
+
+		// the conversions ignore the alpha
+		h1, s1, v1 := c.HSV()
+		h2, s2, v2 := c.Mul(pixel.Alpha(0.5)).HSV()
+		assert.InDeltaSlice(t, []float64{h1, s1, v1}, []float64{h2, s2, v2}, 1e-9)
+	}
+}
+
+func TestRGBA_Lab(t *testing.T) {
+	l, a, b := pixel.RGB(1, 1, 1).Lab()
+	assert.InDeltaSlice(t, []float64{100, 0, 0}, []float64{l, a, b}, 1e-3)
+	l, a, b = pixel.RGB(1, 0, 0).Lab()
+	assert.InDeltaSlice(t, []float64{53.24, 80.09, 67.20}, []float64{l, a, b}, 1e-2)
+
+	l, a, b = pixel.RGB(1, 1, 1).OKLab()
+	assert.InDeltaSlice(t, []float64{1, 0, 0}, []float64{l, a, b}, 1e-4)
+	l, a, b = pixel.RGB(0, 0, 1).OKLab()
+	assert.InDeltaSlice(t, []float64{0.4520, -0.0325, -0.3115}, []float64{l, a, b}, 1e-3)
+
+	r, g, bl := pixel.RGB(0.5, 0.5, 0.5).LinearRGB()
+	assert.InDeltaSlice(t, []float64{0.2140, 0.2140, 0.2140}, []float64{r, g, bl}, 1e-4)
+}
+
+func TestRGBA_Lerp(t *testing.T) {
+	red, blue := pixel.RGB(1, 0, 0), pixel.RGB(0, 0, 1)
+	lerps := map[string]func(c, d pixel.RGBA, t float64) pixel.RGBA{
+		"Lerp":       pixel.RGBA.Lerp,
+		"LerpLinear": pixel.RGBA.LerpLinear,
+		"LerpHSV":    pixel.RGBA.LerpHSV,
+		"LerpOKLab":  pixel.RGBA.LerpOKLab,
+	}
+	for name, lerp := range lerps {
+		assertRGBAInDelta(t, red, lerp(red, blue, 0), 1e-6, name)
+		assertRGBAInDelta(t, blue, lerp(red, blue, 1), 1e-6, name)
+
+		// the alpha is interpolated linearly and the result stays premultiplied
+		c := lerp(red, blue.Mul(pixel.Alpha(0)), 0.5)
+		assert.InDelta(t, 0.5, c.A, 1e-9, name)
+		assert.True(t, c.R <= c.A+1e-9 && c.G <= c.A+1e-9 && c.B <= c.A+1e-9, name)
+	}
+
+	assertRGBAInDelta(t, pixel.RGB(0.5, 0, 0.5), red.Lerp(blue, 0.5), 1e-9)
+	// red and blue meet at magenta going around the hue circle the shorter way
+	assertRGBAInDelta(t, pixel.RGB(1, 0, 1), red.LerpHSV(blue, 0.5), 1e-9)
+	assertRGBAInDelta(t, pixel.RGB(1, 0, 0), red.LerpHSV(pixel.RGB(0.5, 0.5, 0.5), 0), 1e-9)
+	// linear light mixes to a brighter middle than the gamma-encoded values
+	mid := pixel.RGB(0, 0, 0).LerpLinear(pixel.RGB(1, 1, 1), 0.5)
+	assert.InDelta(t, 0.7354, mid.R, 1e-4)
+}
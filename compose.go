@@ -1,16 +1,19 @@
 package pixel
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
 
-// ComposeTarget is a BasicTarget capable of Porter-Duff composition.
+// ComposeTarget is a BasicTarget capable of Porter-Duff composition and separable blending.
 type ComposeTarget interface {
 	BasicTarget
 
-	// SetComposeMethod sets a Porter-Duff composition method to be used.
+	// SetComposeMethod sets a Porter-Duff composition method or a blend mode to be used.
 	SetComposeMethod(ComposeMethod)
 }
 
-// ComposeMethod is a Porter-Duff composition method.
+// ComposeMethod is a Porter-Duff composition method or a separable blend mode.
 type ComposeMethod int
 
 // Here's the list of all available Porter-Duff composition methods. Use ComposeOver for the basic
@@ -29,6 +32,28 @@ const (
 	ComposeCopy
 )
 
+// Here's the list of all available separable blend modes, as known from image editors. They mix
+// the colors of the foreground and the background channel by channel, and then compose the
+// result over the background, so transparent parts of the foreground leave the background
+// untouched.
+//
+// ComposeMultiply darkens and ComposeScreen lightens, which makes them ideal for shadows and
+// lights. ComposeAdd is the additive blending clamped to white, good for glows and particles.
+const (
+	ComposeMultiply ComposeMethod = iota + ComposeCopy + 1
+	ComposeScreen
+	ComposeOverlay
+	ComposeDarken
+	ComposeLighten
+	ComposeColorDodge
+	ComposeColorBurn
+	ComposeHardLight
+	ComposeSoftLight
+	ComposeDifference
+	ComposeExclusion
+	ComposeAdd
+)
+
 // Compose composes two colors together according to the ComposeMethod. A is the foreground, B is
 // the background.
 func (cm ComposeMethod) Compose(a, b RGBA) RGBA {
@@ -57,9 +82,87 @@ func (cm ComposeMethod) Compose(a, b RGBA) RGBA {
 		fa, fb = 1, 1
 	case ComposeCopy:
 		fa, fb = 1, 0
+	case ComposeMultiply, ComposeScreen, ComposeOverlay, ComposeDarken, ComposeLighten,
+		ComposeColorDodge, ComposeColorBurn, ComposeHardLight, ComposeSoftLight,
+		ComposeDifference, ComposeExclusion, ComposeAdd:
+		return cm.blend(a, b)
 	default:
 		panic(errors.New("Compose: invalid ComposeMethod"))
 	}
 
 	return a.Mul(Alpha(fa)).Add(b.Mul(Alpha(fb)))
 }
+
+// blend mixes the colors with a separable blend mode and composes the mix over the background.
+func (cm ComposeMethod) blend(a, b RGBA) RGBA {
+	channel := func(cs, cb float64) float64 {
+		// the blend functions work with straight colors
+		var us, ub float64
+		if a.A > 0 {
+			us = cs / a.A
+		}
+		if b.A > 0 {
+			ub = cb / b.A
+		}
+		return cs*(1-b.A) + cb*(1-a.A) + a.A*b.A*cm.blendChannel(us, ub)
+	}
+	return RGBA{
+		R: channel(a.R, b.R),
+		G: channel(a.G, b.G),
+		B: channel(a.B, b.B),
+		A: a.A + b.A - a.A*b.A,
+	}
+}
+
+// blendChannel mixes a single straight channel of the foreground (s) and the background (b).
+func (cm ComposeMethod) blendChannel(s, b float64) float64 {
+	switch cm {
+	case ComposeMultiply:
+		return s * b
+	case ComposeScreen:
+		return s + b - s*b
+	case ComposeOverlay:
+		return ComposeHardLight.blendChannel(b, s)
+	case ComposeDarken:
+		return math.Min(s, b)
+	case ComposeLighten:
+		return math.Max(s, b)
+	case ComposeColorDodge:
+		switch {
+		case b == 0:
+			return 0
+		case s >= 1:
+			return 1
+		}
+		return math.Min(1, b/(1-s))
+	case ComposeColorBurn:
+		switch {
+		case b >= 1:
+			return 1
+		case s == 0:
+			return 0
+		}
+		return 1 - math.Min(1, (1-b)/s)
+	case ComposeHardLight:
+		if s <= 0.5 {
+			return b * 2 * s
+		}
+		return ComposeScreen.blendChannel(2*s-1, b)
+	case ComposeSoftLight:
+		if s <= 0.5 {
+			return b - (1-2*s)*b*(1-b)
+		}
+		d := math.Sqrt(b)
+		if b <= 0.25 {
+			d = ((16*b-12)*b + 4) * b
+		}
+		return b + (2*s-1)*(d-b)
+	case ComposeDifference:
+		return math.Abs(s - b)
+	case ComposeExclusion:
+		return s + b - 2*s*b
+	case ComposeAdd:
+		return math.Min(1, s+b)
+	}
+	panic(errors.New("Compose: invalid ComposeMethod"))
+}
@@ -0,0 +1,70 @@
+package pixel_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+var blendModes = []pixel.ComposeMethod{
+	pixel.ComposeMultiply,
+	pixel.ComposeScreen,
+	pixel.ComposeOverlay,
+	pixel.ComposeDarken,
+	pixel.ComposeLighten,
+	pixel.ComposeColorDodge,
+	pixel.ComposeColorBurn,
+	pixel.ComposeHardLight,
+	pixel.ComposeSoftLight,
+	pixel.ComposeDifference,
+	pixel.ComposeExclusion,
+	pixel.ComposeAdd,
+}
+
+func TestComposeMethod_Blend(t *testing.T) {
+	fg := pixel.RGB(0.25, 0.5, 0.75)
+	bg := pixel.RGB(0.5, 0.2, 1)
+
+	tests := []struct {
+		cm   pixel.ComposeMethod
+		want pixel.RGBA
+	}{
+		{pixel.ComposeMultiply, pixel.RGB(0.125, 0.1, 0.75)},
+		{pixel.ComposeScreen, pixel.RGB(0.625, 0.6, 1)},
+		{pixel.ComposeOverlay, pixel.RGB(0.25, 0.2, 1)},
+		{pixel.ComposeDarken, pixel.RGB(0.25, 0.2, 0.75)},
+		{pixel.ComposeLighten, pixel.RGB(0.5, 0.5, 1)},
+		{pixel.ComposeColorDodge, pixel.RGB(0.6666666666666666, 0.4, 1)},
+		{pixel.ComposeColorBurn, pixel.RGB(0, 0, 1)},
+		{pixel.ComposeHardLight, pixel.RGB(0.25, 0.2, 1)},
+		{pixel.ComposeSoftLight, pixel.RGB(0.375, 0.2, 1)},
+		{pixel.ComposeDifference, pixel.RGB(0.25, 0.3, 0.25)},
+		{pixel.ComposeExclusion, pixel.RGB(0.5, 0.5, 0.25)},
+		{pixel.ComposeAdd, pixel.RGB(0.75, 0.7, 1)},
+	}
+	for _, tt := range tests {
+		assertRGBAInDelta(t, tt.want, tt.cm.Compose(fg, bg), 1e-9, "compose method %d", tt.cm)
+	}
+}
+
+func TestComposeMethod_BlendTransparent(t *testing.T) {
+	fg := pixel.RGB(0.25, 0.5, 0.75).Mul(pixel.Alpha(0.8))
+	bg := pixel.RGB(0.5, 0.2, 1).Mul(pixel.Alpha(0.6))
+
+	for _, cm := range blendModes {
+		// with nothing to blend with, the blend modes are just like ComposeOver
+		assertRGBAInDelta(t, fg, cm.Compose(fg, pixel.RGBA{}), 1e-9, "compose method %d", cm)
+		assertRGBAInDelta(t, bg, cm.Compose(pixel.RGBA{}, bg), 1e-9, "compose method %d", cm)
+
+		c := cm.Compose(fg, bg)
+		assert.InDelta(t, pixel.ComposeOver.Compose(fg, bg).A, c.A, 1e-9, "compose method %d", cm)
+		assert.True(t, c.R <= c.A && c.G <= c.A && c.B <= c.A, "compose method %d", cm)
+	}
+}
+
+func TestComposeMethod_Invalid(t *testing.T) {
+	assert.Panics(t, func() {
+		pixel.ComposeMethod(-1).Compose(pixel.Alpha(1), pixel.Alpha(1))
+	})
+}
@@ -74,6 +74,10 @@ func (td *TrianglesData) updateData(t Triangles) {
 		copy(*td, *t)
 		return
 	}
+	if t, ok := t.(*IndexedTrianglesData); ok {
+		copy(*td, t.TrianglesData)
+		return
+	}
 
 	// slow path manual copy
 	if t, ok := t.(TrianglesPosition); ok {
@@ -135,6 +139,132 @@ func (td *TrianglesData) ClipRect(i int) (rect Rect, has bool) {
 	return (*td)[i].ClipRect, (*td)[i].IsClipped
 }
 
+// IndexedTrianglesData is TrianglesData with a list of indices, where each three indices form a
+// triangle of the vertices they point to. Vertices shared by several triangles are stored only
+// once, so a quad takes four vertices instead of six.
+//
+// IndexedTrianglesData implements TrianglesIndexed in addition to all the properties of
+// TrianglesData. Targets which don't support TrianglesIndexed get the triangles with the shared
+// vertices duplicated when drawn using a Drawer.
+//
+//   quad := pixel.MakeIndexedTrianglesData(4, []int{0, 1, 2, 0, 2, 3})
+type IndexedTrianglesData struct {
+	TrianglesData
+	indices []int
+}
+
+var _ TrianglesIndexed = (*IndexedTrianglesData)(nil)
+
+// MakeIndexedTrianglesData creates IndexedTrianglesData with len vertices initialized with default
+// property values and the given indices.
+func MakeIndexedTrianglesData(len int, indices []int) *IndexedTrianglesData {
+	return &IndexedTrianglesData{
+		TrianglesData: *MakeTrianglesData(len),
+		indices:       indices,
+	}
+}
+
+// Indices returns the indices of the IndexedTrianglesData. They're always indexed.
+func (itd *IndexedTrianglesData) Indices() (indices []int, is bool) {
+	return itd.indices, true
+}
+
+// SetIndices sets the indices of the IndexedTrianglesData. The slice is not copied.
+func (itd *IndexedTrianglesData) SetIndices(indices []int) {
+	itd.indices = indices
+}
+
+// SetLen resizes the vertices of IndexedTrianglesData to len, while keeping the original content.
+//
+// If the vertices shrink, the triangles using the removed vertices are removed as well.
+func (itd *IndexedTrianglesData) SetLen(length int) {
+	if length < itd.Len() {
+		itd.indices = sliceIndices(itd.indices, 0, length)
+	}
+	itd.TrianglesData.SetLen(length)
+}
+
+// Slice returns a sub-Triangles of this IndexedTrianglesData covering the vertices in range [i, j).
+// The slice shares the vertices, but it gets its own copy of the triangles which only use the
+// vertices in the range, with the indices rebased to the start of the range.
+func (itd *IndexedTrianglesData) Slice(i, j int) Triangles {
+	return &IndexedTrianglesData{
+		TrianglesData: itd.TrianglesData[i:j],
+		indices:       sliceIndices(itd.indices, i, j),
+	}
+}
+
+// Update copies vertex properties and the indices from the supplied Triangles into this
+// IndexedTrianglesData. If the supplied Triangles aren't indexed, the indices are set so that each
+// three consecutive vertices form a triangle.
+//
+// The supplied Triangles must have the same number of vertices.
+func (itd *IndexedTrianglesData) Update(t Triangles) {
+	if itd.Len() != t.Len() {
+		panic(fmt.Errorf("(%T).Update: invalid triangles length", itd))
+	}
+	itd.TrianglesData.updateData(t)
+	if indices, ok := indicesOf(t); ok {
+		itd.indices = append(itd.indices[:0], indices...)
+		return
+	}
+	itd.indices = itd.indices[:0]
+	for i := 0; i < itd.Len()/3*3; i++ {
+		itd.indices = append(itd.indices, i)
+	}
+}
+
+// Copy returns an exact independent copy of this IndexedTrianglesData.
+func (itd *IndexedTrianglesData) Copy() Triangles {
+	copyItd := MakeIndexedTrianglesData(itd.Len(), nil)
+	copyItd.Update(itd)
+	return copyItd
+}
+
+// indicesOf returns the indices of the Triangles and whether they're indexed.
+func indicesOf(t Triangles) (indices []int, is bool) {
+	if t, ok := t.(TrianglesIndexed); ok {
+		return t.Indices()
+	}
+	return nil, false
+}
+
+// sliceIndices returns a copy of the triangles of the indices which only use the vertices in range
+// [i, j), with the indices rebased to the start of the range.
+func sliceIndices(indices []int, i, j int) []int {
+	sliced := []int{}
+	for k := 0; k+2 < len(indices); k += 3 {
+		a, b, c := indices[k], indices[k+1], indices[k+2]
+		if a >= i && a < j && b >= i && b < j && c >= i && c < j {
+			sliced = append(sliced, a-i, b-i, c-i)
+		}
+	}
+	return sliced
+}
+
+// unindex sets dst to the triangles of t listed by the indices, with the shared vertices
+// duplicated, and returns it. If dst is nil, new TrianglesData is allocated.
+func unindex(dst *TrianglesData, t Triangles, indices []int) *TrianglesData {
+	var vertices TrianglesData
+	if itd, ok := t.(*IndexedTrianglesData); ok {
+		vertices = itd.TrianglesData
+	} else {
+		vertices = *MakeTrianglesData(t.Len())
+		vertices.updateData(t)
+	}
+	if dst == nil {
+		dst = &TrianglesData{}
+	}
+	dst.SetLen(len(indices) / 3 * 3)
+	for i := range *dst {
+		if indices[i] < 0 || indices[i] >= len(vertices) {
+			panic(fmt.Errorf("(%T).Indices: index %d out of range of %d vertices", t, indices[i], len(vertices)))
+		}
+		(*dst)[i] = vertices[indices[i]]
+	}
+	return dst
+}
+
 // PictureData specifies an in-memory rectangular area of pixels and implements Picture and
 // PictureColor.
 //
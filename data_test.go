@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
 )
 
 func BenchmarkMakeTrianglesData(b *testing.B) {
@@ -298,3 +299,38 @@ func BenchmarkTrianglesData_ClipRect(b *testing.B) {
 		})
 	}
 }
+
+func TestIndexedTrianglesData(t *testing.T) {
+	itd := pixel.MakeIndexedTrianglesData(5, []int{0, 1, 2, 0, 2, 3, 2, 3, 4})
+	for i := 0; i < itd.Len(); i++ {
+		itd.TrianglesData[i].Position = pixel.V(float64(i), 0)
+	}
+
+	c := itd.Copy().(*pixel.IndexedTrianglesData)
+	indices, indexed := c.Indices()
+	assert.True(t, indexed)
+	assert.Equal(t, []int{0, 1, 2, 0, 2, 3, 2, 3, 4}, indices)
+	assert.Equal(t, pixel.V(4, 0), c.Position(4))
+
+	// removing a vertex removes the triangles using it
+	c.SetLen(4)
+	indices, _ = c.Indices()
+	assert.Equal(t, []int{0, 1, 2, 0, 2, 3}, indices)
+
+	// slices share the vertices and keep the triangles inside of them
+	s := itd.Slice(2, 5).(*pixel.IndexedTrianglesData)
+	s.TrianglesData[0].Position = pixel.V(10, 10)
+	assert.Equal(t, pixel.V(10, 10), itd.Position(2))
+	indices, _ = s.Indices()
+	assert.Equal(t, []int{0, 1, 2}, indices)
+
+	// plain TrianglesData copy only the vertices
+	td := pixel.MakeTrianglesData(5)
+	td.Update(itd)
+	assert.Equal(t, pixel.V(10, 10), td.Position(2))
+
+	// updating from plain TrianglesData makes consecutive vertices form triangles
+	itd.Update(td)
+	indices, _ = itd.Indices()
+	assert.Equal(t, []int{0, 1, 2}, indices)
+}
@@ -0,0 +1,167 @@
+package pixel
+
+import (
+	"image/color"
+	"sort"
+)
+
+// DepthBatch is a Target which sorts the objects drawn onto it by their layer and depth. When
+// drawn onto another Target, the objects are drawn layer by layer from the lowest one, and within
+// a layer back-to-front, from the highest depth to the lowest. Objects with equal layer and depth
+// keep the order they were drawn in.
+//
+// The layer and the depth of the following draws are set by SetLayer and SetDepth, just like the
+// Matrix is set by SetMatrix. Each Draw call of an object onto the DepthBatch makes a single
+// object which is never split.
+//
+// For example, a top-down game sorting its characters by their y coordinate, so that the ones
+// closer to the bottom of the screen cover the ones behind them:
+//
+//   db := pixel.NewDepthBatch()
+//   db.SetLayer(0)
+//   ground.Draw(db, pixel.IM)
+//   db.SetLayer(1)
+//   for _, c := range characters {
+//       db.SetDepth(c.Pos.Y)
+//       c.Sprite.Draw(db, pixel.IM.Moved(c.Pos))
+//   }
+//   db.Draw(win)
+//
+// The objects are drawn using a MultiBatch, so objects with different Pictures may be drawn
+// together.
+type DepthBatch struct {
+	tri     TrianglesData
+	objects []depthObject
+	mb      *MultiBatch
+	sorted  bool
+
+	layer int
+	depth float64
+	mat   Matrix
+	col   RGBA
+}
+
+var _ BasicTarget = (*DepthBatch)(nil)
+
+// depthObject is a range of vertices of a DepthBatch drawn with the same layer, depth and Picture.
+type depthObject struct {
+	layer      int
+	depth      float64
+	pic        Picture
+	start, end int
+}
+
+// NewDepthBatch creates an empty DepthBatch. The initial layer and depth are zero.
+func NewDepthBatch() *DepthBatch {
+	db := &DepthBatch{mb: NewMultiBatch()}
+	db.SetMatrix(IM)
+	db.SetColorMask(Alpha(1))
+	return db
+}
+
+// SetLayer sets the layer of the following draws. Objects in higher layers are drawn over the
+// objects in lower layers regardless of their depth.
+func (db *DepthBatch) SetLayer(layer int) {
+	db.layer = layer
+}
+
+// Layer returns the layer of the following draws.
+func (db *DepthBatch) Layer() int {
+	return db.layer
+}
+
+// SetDepth sets the depth of the following draws. Within a layer, objects with a lower depth are
+// drawn over the objects with a higher depth.
+func (db *DepthBatch) SetDepth(depth float64) {
+	db.depth = depth
+}
+
+// Depth returns the depth of the following draws.
+func (db *DepthBatch) Depth() float64 {
+	return db.depth
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (db *DepthBatch) SetMatrix(m Matrix) {
+	db.mat = m
+}
+
+// SetColorMask sets a mask color used in the following draws onto the DepthBatch.
+func (db *DepthBatch) SetColorMask(c color.Color) {
+	if c == nil {
+		db.col = Alpha(1)
+		return
+	}
+	db.col = ToRGBA(c)
+}
+
+// Clear removes all objects from the DepthBatch. The layer, the depth, the Matrix and the color
+// mask are kept.
+func (db *DepthBatch) Clear() {
+	db.tri.SetLen(0)
+	db.objects = db.objects[:0]
+	db.sorted = false
+}
+
+// Len returns the number of objects in the DepthBatch.
+func (db *DepthBatch) Len() int {
+	return len(db.objects)
+}
+
+// Draw draws all objects that are currently in the DepthBatch onto another Target, sorted by
+// their layers and depths.
+func (db *DepthBatch) Draw(t Target) {
+	if !db.sorted {
+		// the objects sorted before stay in order, the new ones come after the equal ones
+		sort.SliceStable(db.objects, func(i, j int) bool {
+			a, b := db.objects[i], db.objects[j]
+			if a.layer != b.layer {
+				return a.layer < b.layer
+			}
+			return a.depth > b.depth
+		})
+		db.mb.Clear()
+		for _, o := range db.objects {
+			tri := db.tri[o.start:o.end]
+			db.mb.add(&tri, o.pic)
+		}
+		db.sorted = true
+	}
+	db.mb.Draw(t)
+}
+
+// MakeTriangles returns a specialized copy of the provided Triangles that draws onto this
+// DepthBatch.
+func (db *DepthBatch) MakeTriangles(t Triangles) TargetTriangles {
+	return &multiBatchTriangles{
+		tri: t.Copy(),
+		tmp: MakeTrianglesData(t.Len()),
+		dst: db,
+	}
+}
+
+// MakePicture returns a specialized copy of the provided Picture that draws onto this
+// DepthBatch. Any Picture is accepted.
+func (db *DepthBatch) MakePicture(p Picture) TargetPicture {
+	return &multiBatchPicture{
+		pic: p,
+		dst: db,
+	}
+}
+
+func (db *DepthBatch) transform() (Matrix, RGBA) {
+	return db.mat, db.col
+}
+
+func (db *DepthBatch) add(tri *TrianglesData, pic Picture) {
+	start := db.tri.Len()
+	db.tri = append(db.tri, *tri...)
+	db.objects = append(db.objects, depthObject{
+		layer: db.layer,
+		depth: db.depth,
+		pic:   pic,
+		start: start,
+		end:   db.tri.Len(),
+	})
+	db.sorted = false
+}
@@ -0,0 +1,56 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepthBatch(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	green := color.RGBA{G: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	sprite := func(c color.RGBA) *pixel.Sprite {
+		return pixel.NewSprite(solidPicture(c), pixel.R(0, 0, 2, 2))
+	}
+	at := pixel.IM.Moved(pixel.V(1, 1))
+
+	drawn := func(db *pixel.DepthBatch) color.RGBA {
+		pd := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+		db.Draw(pixel.NewRaster(pd))
+		return pd.Pix[pd.Index(pixel.V(1, 1))]
+	}
+
+	// lower depth covers higher depth, regardless of the drawing order
+	db := pixel.NewDepthBatch()
+	db.SetDepth(1)
+	sprite(red).Draw(db, at)
+	db.SetDepth(5)
+	sprite(green).Draw(db, at)
+	assert.Equal(t, 2, db.Len())
+	assert.Equal(t, red, drawn(db))
+
+	// equal depths keep the drawing order, even when added after sorting
+	db.SetDepth(1)
+	sprite(blue).Draw(db, at)
+	assert.Equal(t, blue, drawn(db))
+	db.SetDepth(1)
+	sprite(red).Draw(db, at)
+	assert.Equal(t, red, drawn(db))
+
+	// higher layers cover lower layers regardless of the depth
+	db.SetLayer(-1)
+	db.SetDepth(-100)
+	sprite(green).Draw(db, at)
+	assert.Equal(t, red, drawn(db))
+	db.SetLayer(1)
+	db.SetDepth(100)
+	sprite(blue).Draw(db, at)
+	assert.Equal(t, blue, drawn(db))
+
+	db.Clear()
+	assert.Equal(t, 0, db.Len())
+	assert.Equal(t, color.RGBA{}, drawn(db))
+}
@@ -1,5 +1,7 @@
 package pixel
 
+import "container/list"
+
 // Drawer glues all the fundamental interfaces (Target, Triangles, Picture) into a coherent and the
 // only intended usage pattern.
 //
@@ -13,18 +15,28 @@ package pixel
 // If Triangles is nil, nothing will be drawn. If Picture is nil, Triangles will be drawn without a
 // Picture.
 //
+// If the Triangles are TrianglesIndexed and the Target doesn't support them, Drawer duplicates the
+// shared vertices, so that they can be drawn as plain triangles.
+//
 // Whenever you change the Triangles, call Dirty to notify Drawer that Triangles changed. You don't
 // need to notify Drawer about a change of the Picture.
 //
-// Note, that Drawer caches the results of MakePicture from Targets it's drawn to for each Picture
-// it's set to. What it means is that using a Drawer with an unbounded number of Pictures leads to a
-// memory leak, since Drawer caches them and never forgets. In such a situation, create a new Drawer
-// for each Picture.
+// Note, that if Cached is true, Drawer caches the results of MakePicture from Targets it's drawn to
+// for each Picture it's set to. By default, it never forgets them, so using a Drawer with an
+// unbounded number of Pictures leads to a memory leak. In such a situation, set MaxCached to
+// bound the cache, or call ForgetPicture when a Picture is no longer used.
+//
+// The evicted and forgotten results of MakePicture are released if they're ReleasablePictures,
+// such as the textures made by pixelgl.Canvas from Pictures which aren't on the GPU.
 type Drawer struct {
 	Triangles Triangles
 	Picture   Picture
 	Cached    bool
 
+	// MaxCached is the maximal number of Pictures cached for each Target. If it's zero, the
+	// number is unbounded. Otherwise, the least recently drawn Pictures are evicted.
+	MaxCached int
+
 	targets    map[Target]*drawerTarget
 	allTargets []*drawerTarget
 	inited     bool
@@ -32,8 +44,12 @@ type Drawer struct {
 
 type drawerTarget struct {
 	tris  TargetTriangles
-	pics  map[Picture]TargetPicture
+	pics  *pictureCache
 	clean bool
+
+	// unindexed holds the Triangles with the shared vertices duplicated, for Targets which don't
+	// support TrianglesIndexed
+	unindexed *TrianglesData
 }
 
 func (d *Drawer) lazyInit() {
@@ -43,6 +59,36 @@ func (d *Drawer) lazyInit() {
 	}
 }
 
+// ForgetPicture removes the results of MakePicture of the Picture from the cache of all Targets,
+// releasing them if possible. If the Picture is the Drawer's Picture, it's made again on the next
+// Draw.
+func (d *Drawer) ForgetPicture(pic Picture) {
+	d.lazyInit()
+
+	for _, dt := range d.allTargets {
+		dt.pics.forget(pic)
+	}
+}
+
+// ForgetTarget removes everything the Drawer made by the Target, releasing the Pictures if
+// possible. The Drawer can still be drawn onto the Target later.
+func (d *Drawer) ForgetTarget(t Target) {
+	d.lazyInit()
+
+	dt := d.targets[t]
+	if dt == nil {
+		return
+	}
+	dt.pics.clear()
+	delete(d.targets, t)
+	for i := range d.allTargets {
+		if d.allTargets[i] == dt {
+			d.allTargets = append(d.allTargets[:i], d.allTargets[i+1:]...)
+			break
+		}
+	}
+}
+
 // Dirty marks the Triangles of this Drawer as changed. If not called, changes will not be visible
 // when drawing.
 func (d *Drawer) Dirty() {
@@ -67,7 +113,7 @@ func (d *Drawer) Draw(t Target) {
 	dt := d.targets[t]
 	if dt == nil {
 		dt = &drawerTarget{
-			pics: make(map[Picture]TargetPicture),
+			pics: newPictureCache(),
 		}
 		d.targets[t] = dt
 		d.allTargets = append(d.allTargets, dt)
@@ -75,12 +121,21 @@ func (d *Drawer) Draw(t Target) {
 
 	if dt.tris == nil {
 		dt.tris = t.MakeTriangles(d.Triangles)
-		dt.clean = true
+		_, supported := dt.tris.(TrianglesIndexed)
+		_, indexed := indicesOf(d.Triangles)
+		dt.clean = supported || !indexed
 	}
 
 	if !dt.clean {
-		dt.tris.SetLen(d.Triangles.Len())
-		dt.tris.Update(d.Triangles)
+		_, supported := dt.tris.(TrianglesIndexed)
+		if indices, indexed := indicesOf(d.Triangles); indexed && !supported {
+			dt.unindexed = unindex(dt.unindexed, d.Triangles, indices)
+			dt.tris.SetLen(dt.unindexed.Len())
+			dt.tris.Update(dt.unindexed)
+		} else {
+			dt.tris.SetLen(d.Triangles.Len())
+			dt.tris.Update(d.Triangles)
+		}
 		dt.clean = true
 	}
 
@@ -89,14 +144,82 @@ func (d *Drawer) Draw(t Target) {
 		return
 	}
 
-	pic := dt.pics[d.Picture]
+	pic := dt.pics.get(d.Picture)
 	if pic == nil {
 		pic = t.MakePicture(d.Picture)
 
 		if d.Cached {
-			dt.pics[d.Picture] = pic
+			dt.pics.put(d.Picture, pic)
 		}
 	}
+	if d.Cached {
+		dt.pics.evict(d.MaxCached)
+	}
 
 	pic.Draw(dt.tris)
 }
+
+// pictureCache caches the results of MakePicture of a single Target in the order of their use.
+type pictureCache struct {
+	pics  map[Picture]*list.Element
+	order *list.List // of *pictureCacheEntry, the most recently used first
+}
+
+type pictureCacheEntry struct {
+	pic Picture
+	tp  TargetPicture
+}
+
+func newPictureCache() *pictureCache {
+	return &pictureCache{
+		pics:  make(map[Picture]*list.Element),
+		order: list.New(),
+	}
+}
+
+// get returns the cached TargetPicture of the Picture and marks it as the most recently used, or
+// returns nil if it's not cached.
+func (pc *pictureCache) get(pic Picture) TargetPicture {
+	e := pc.pics[pic]
+	if e == nil {
+		return nil
+	}
+	pc.order.MoveToFront(e)
+	return e.Value.(*pictureCacheEntry).tp
+}
+
+// put caches the TargetPicture of the Picture as the most recently used.
+func (pc *pictureCache) put(pic Picture, tp TargetPicture) {
+	pc.forget(pic)
+	pc.pics[pic] = pc.order.PushFront(&pictureCacheEntry{pic: pic, tp: tp})
+}
+
+// evict removes and releases the least recently used TargetPictures over the maximal number. Zero
+// maximum means no limit.
+func (pc *pictureCache) evict(max int) {
+	for max > 0 && pc.order.Len() > max {
+		pc.remove(pc.order.Back())
+	}
+}
+
+// forget removes and releases the TargetPicture of the Picture.
+func (pc *pictureCache) forget(pic Picture) {
+	if e := pc.pics[pic]; e != nil {
+		pc.remove(e)
+	}
+}
+
+// clear removes and releases all TargetPictures.
+func (pc *pictureCache) clear() {
+	for pc.order.Len() > 0 {
+		pc.remove(pc.order.Back())
+	}
+}
+
+func (pc *pictureCache) remove(e *list.Element) {
+	entry := pc.order.Remove(e).(*pictureCacheEntry)
+	delete(pc.pics, entry.pic)
+	if rp, ok := entry.tp.(ReleasablePicture); ok {
+		rp.Release()
+	}
+}
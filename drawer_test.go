@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
 )
 
 func BenchmarkSpriteDrawBatch(b *testing.B) {
@@ -16,3 +17,115 @@ func BenchmarkSpriteDrawBatch(b *testing.B) {
 		sprite.Draw(batch, pixel.IM)
 	}
 }
+
+func TestDrawer_Unindexed(t *testing.T) {
+	quad := pixel.MakeIndexedTrianglesData(4, []int{0, 1, 2, 0, 2, 3})
+	for i, v := range []pixel.Vec{pixel.V(0, 0), pixel.V(1, 0), pixel.V(1, 1), pixel.V(0, 1)} {
+		quad.TrianglesData[i].Position = v
+	}
+
+	// recordingTarget doesn't support TrianglesIndexed, so it gets the shared vertices duplicated
+	rt := &recordingTarget{tri: &pixel.TrianglesData{}}
+	d := pixel.Drawer{Triangles: quad}
+	d.Draw(rt)
+	assert.Equal(t, 6, rt.tri.Len())
+	for i, want := range []pixel.Vec{
+		pixel.V(0, 0), pixel.V(1, 0), pixel.V(1, 1), pixel.V(0, 0), pixel.V(1, 1), pixel.V(0, 1),
+	} {
+		assert.Equal(t, want, rt.tri.Position(i))
+	}
+
+	quad.SetIndices([]int{0, 1, 2})
+	d.Dirty()
+	d.Draw(rt)
+	assert.Equal(t, 3, rt.tri.Len())
+}
+
+func TestBatch_Indexed(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+
+	// an indexed container keeps the vertices shared
+	cont := pixel.MakeIndexedTrianglesData(0, nil)
+	batch := pixel.NewBatch(cont, pic)
+	sprite.Draw(batch, pixel.IM)
+	sprite.Draw(batch, pixel.IM.Moved(pixel.V(10, 0)))
+	indices, _ := cont.Indices()
+	assert.Equal(t, 8, cont.Len())
+	assert.Equal(t, []int{0, 1, 2, 0, 2, 3, 4, 5, 6, 4, 6, 7}, indices)
+	assert.Equal(t, pixel.V(8, -2), cont.Position(4))
+
+	batch.Clear()
+	indices, _ = cont.Indices()
+	assert.Empty(t, indices)
+
+	// a plain container gets them duplicated
+	td := &pixel.TrianglesData{}
+	batch = pixel.NewBatch(td, pic)
+	sprite.Draw(batch, pixel.IM)
+	sprite.Draw(batch, pixel.IM)
+	assert.Equal(t, 12, td.Len())
+	assert.Equal(t, td.Position(0), td.Position(3))
+}
+
+// releaseTarget is a recordingTarget which counts the made and the released Pictures.
+type releaseTarget struct {
+	recordingTarget
+	made     int
+	released map[pixel.Picture]int
+}
+
+type releasablePicture struct {
+	recordingPicture
+	dst *releaseTarget
+}
+
+func (rp *releasablePicture) Release() {
+	rp.dst.released[rp.Picture]++
+}
+
+func (rt *releaseTarget) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	rt.made++
+	return &releasablePicture{recordingPicture{p}, rt}
+}
+
+func TestDrawer_MaxCached(t *testing.T) {
+	pics := []pixel.Picture{
+		pixel.MakePictureData(pixel.R(0, 0, 1, 1)),
+		pixel.MakePictureData(pixel.R(0, 0, 1, 1)),
+		pixel.MakePictureData(pixel.R(0, 0, 1, 1)),
+	}
+	rt := &releaseTarget{
+		recordingTarget: recordingTarget{tri: &pixel.TrianglesData{}},
+		released:        make(map[pixel.Picture]int),
+	}
+	d := pixel.Drawer{Triangles: pixel.MakeTrianglesData(3), Cached: true, MaxCached: 2}
+	draw := func(pic pixel.Picture) {
+		d.Picture = pic
+		d.Draw(rt)
+	}
+
+	draw(pics[0])
+	draw(pics[1])
+	draw(pics[0])
+	assert.Equal(t, 2, rt.made)
+	assert.Empty(t, rt.released)
+
+	// pics[1] is the least recently used
+	draw(pics[2])
+	assert.Equal(t, 3, rt.made)
+	assert.Equal(t, map[pixel.Picture]int{pics[1]: 1}, rt.released)
+	draw(pics[0])
+	assert.Equal(t, 3, rt.made)
+
+	d.ForgetPicture(pics[0])
+	assert.Equal(t, 1, rt.released[pics[0]])
+	draw(pics[0])
+	assert.Equal(t, 4, rt.made)
+
+	d.ForgetTarget(rt)
+	assert.Equal(t, 2, rt.released[pics[0]])
+	assert.Equal(t, 1, rt.released[pics[2]])
+	draw(pics[0])
+	assert.Equal(t, 5, rt.made)
+}
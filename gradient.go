@@ -0,0 +1,95 @@
+package pixel
+
+import (
+	"math"
+	"sort"
+)
+
+// GradientStop is a color at a position in a Gradient.
+type GradientStop struct {
+	Pos   float64
+	Color RGBA
+}
+
+// Gradient is a smooth transition between several colors, the Stops. Between two adjacent Stops,
+// the colors are interpolated using the Lerp function.
+//
+//   sky := pixel.Gradient{
+//       Stops: []pixel.GradientStop{
+//           {Pos: 0, Color: pixel.RGB(0.1, 0.1, 0.3)},
+//           {Pos: 0.6, Color: pixel.RGB(0.9, 0.5, 0.3)},
+//           {Pos: 1, Color: pixel.RGB(1, 0.9, 0.6)},
+//       },
+//       Lerp: pixel.RGBA.LerpOKLab,
+//   }
+//   sky.At(0.3) // somewhere between the night and the sunset
+type Gradient struct {
+	// Stops are the colors of the Gradient. They must be sorted by their positions. Multiple
+	// Stops at the same position make a sharp edge.
+	Stops []GradientStop
+
+	// Lerp interpolates between the colors of two Stops. If it's nil, RGBA.Lerp is used. Methods
+	// of RGBA, such as RGBA.LerpOKLab, can be used directly.
+	Lerp func(c, d RGBA, t float64) RGBA
+}
+
+// At returns the color of the Gradient at the given position. Positions before the first Stop
+// take the color of the first Stop and positions after the last Stop take the color of the last
+// Stop. NaN takes the color of the first Stop. A Gradient with no Stops is transparent.
+func (g Gradient) At(pos float64) RGBA {
+	stops := g.Stops
+	switch {
+	case len(stops) == 0:
+		return RGBA{}
+	case pos <= stops[0].Pos || math.IsNaN(pos):
+		return stops[0].Color
+	case pos >= stops[len(stops)-1].Pos:
+		return stops[len(stops)-1].Color
+	}
+
+	// the first Stop after the position
+	i := sort.Search(len(stops), func(i int) bool { return stops[i].Pos > pos })
+	a, b := stops[i-1], stops[i]
+	lerp := g.Lerp
+	if lerp == nil {
+		lerp = RGBA.Lerp
+	}
+	return lerp(a.Color, b.Color, (pos-a.Pos)/(b.Pos-a.Pos))
+}
+
+// Fill renders the Gradient into the PictureData as a linear gradient. The positions of the
+// Gradient run along the line from the point from (position 0) to the point to (position 1).
+//
+//   pd := pixel.MakePictureData(pixel.R(0, 0, 256, 16))
+//   g.Fill(pd, pixel.V(0, 0), pixel.V(256, 0))
+func (g Gradient) Fill(pd *PictureData, from, to Vec) {
+	dir := from.To(to)
+	sqLen := dir.SqLen()
+	g.fill(pd, func(u Vec) float64 {
+		if sqLen == 0 {
+			return 0
+		}
+		return from.To(u).Dot(dir) / sqLen
+	})
+}
+
+// FillRadial renders the Gradient into the PictureData as a radial gradient. The positions of the
+// Gradient run from the center (position 0) to the circle of the given radius (position 1).
+func (g Gradient) FillRadial(pd *PictureData, center Vec, radius float64) {
+	g.fill(pd, func(u Vec) float64 {
+		if radius == 0 {
+			return math.Inf(+1)
+		}
+		return center.To(u).Len() / radius
+	})
+}
+
+// fill sets each pixel of the PictureData to the color of the Gradient at the position the
+// function returns for the center of the pixel.
+func (g Gradient) fill(pd *PictureData, pos func(Vec) float64) {
+	origin := pd.Rect.Min.Map(math.Floor)
+	for i := range pd.Pix {
+		center := origin.Add(V(float64(i%pd.Stride)+0.5, float64(i/pd.Stride)+0.5))
+		pd.Pix[i] = toColorRGBA(g.At(pos(center)))
+	}
+}
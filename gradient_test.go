@@ -0,0 +1,58 @@
+package pixel_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradient_At(t *testing.T) {
+	g := pixel.Gradient{
+		Stops: []pixel.GradientStop{
+			{Pos: 0, Color: pixel.RGB(1, 0, 0)},
+			{Pos: 0.5, Color: pixel.RGB(0, 1, 0)},
+			{Pos: 0.5, Color: pixel.RGB(0, 0, 1)},
+			{Pos: 1, Color: pixel.RGB(1, 1, 1)},
+		},
+	}
+	assert.Equal(t, pixel.RGB(1, 0, 0), g.At(-1))
+	assert.Equal(t, pixel.RGB(1, 0, 0), g.At(0))
+	assertRGBAInDelta(t, pixel.RGB(0.5, 0.5, 0), g.At(0.25), 1e-9)
+	assertRGBAInDelta(t, pixel.RGB(0, 1, 0), g.At(0.5-1e-12), 1e-9)
+	assertRGBAInDelta(t, pixel.RGB(0, 0, 1), g.At(0.5), 1e-9)
+	assertRGBAInDelta(t, pixel.RGB(0.5, 0.5, 1), g.At(0.75), 1e-9)
+	assert.Equal(t, pixel.RGB(1, 1, 1), g.At(2))
+	assert.Equal(t, pixel.RGB(1, 0, 0), g.At(math.NaN()))
+
+	g.Lerp = pixel.RGBA.LerpHSV
+	assertRGBAInDelta(t, pixel.RGB(1, 1, 0), g.At(0.25), 1e-9)
+
+	assert.Equal(t, pixel.RGBA{}, pixel.Gradient{}.At(0.5))
+}
+
+func TestGradient_Fill(t *testing.T) {
+	g := pixel.Gradient{
+		Stops: []pixel.GradientStop{
+			{Pos: 0, Color: pixel.RGB(0, 0, 0)},
+			{Pos: 1, Color: pixel.RGB(1, 1, 1)},
+		},
+	}
+
+	pd := pixel.MakePictureData(pixel.R(10, 0, 14, 2))
+	g.Fill(pd, pixel.V(10, 0), pixel.V(14, 0))
+	for y := 0; y < 2; y++ {
+		for x, v := range []uint8{32, 96, 159, 223} {
+			at := pixel.V(10+float64(x)+0.5, float64(y)+0.5)
+			assert.Equal(t, color.RGBA{v, v, v, 255}, pd.Pix[pd.Index(at)], at)
+		}
+	}
+
+	pd = pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	g.FillRadial(pd, pixel.V(2, 2), 2)
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(1.5, 1.5))], pd.Pix[pd.Index(pixel.V(2.5, 2.5))])
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, pd.Pix[pd.Index(pixel.V(0.5, 0.5))])
+	assert.Less(t, pd.Pix[pd.Index(pixel.V(1.5, 1.5))].R, pd.Pix[pd.Index(pixel.V(0.5, 1.5))].R)
+}
@@ -0,0 +1,83 @@
+package pixel
+
+import "sort"
+
+// ConvexHull returns the smallest convex Polygon containing all of the points. The Polygon is
+// wound counter-clockwise, starts at the lowest leftmost point and has no collinear vertices.
+//
+// If all of the points are the same, the result has one vertex. If they all lie on a line, the
+// result has two vertices, the ends of the line. ConvexHull returns nil for no points.
+//
+// Time complexity is O(n log n).
+func ConvexHull(points []Vec) Polygon {
+	if len(points) == 0 {
+		return nil
+	}
+	sorted := append([]Vec(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Y != sorted[j].Y {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	unique := sorted[:1]
+	for _, v := range sorted[1:] {
+		if v != unique[len(unique)-1] {
+			unique = append(unique, v)
+		}
+	}
+	if len(unique) == 1 {
+		return Polygon{unique[0]}
+	}
+
+	// Andrew's monotone chain: the right chain goes up from the lowest point, the left chain
+	// comes back down and each of them ends where the other one starts
+	chain := func(points []Vec) []Vec {
+		var c []Vec
+		for _, v := range points {
+			for len(c) >= 2 && c[len(c)-2].To(c[len(c)-1]).Cross(c[len(c)-1].To(v)) <= 0 {
+				c = c[:len(c)-1]
+			}
+			c = append(c, v)
+		}
+		return c[:len(c)-1]
+	}
+	reversed := make([]Vec, len(unique))
+	for i, v := range unique {
+		reversed[len(unique)-1-i] = v
+	}
+	hull := append(Polygon(chain(unique)), chain(reversed)...)
+	return hull
+}
+
+// MinkowskiSum returns the Minkowski sum of this and the other convex Polygon, which is the set
+// of all sums of a point from one Polygon and a point from the other one. It's the area covered
+// by sliding the other Polygon around with its origin inside this Polygon:
+//
+//   obstacle := pixel.Polygon{...}
+//   agent := pixel.R(-8, -8, 8, 8) // the agent's shape around its position
+//   v := agent.Vertices()
+//   walls := obstacle.MinkowskiSum(pixel.Polygon(v[:])) // places the agent's position can't go
+//
+// The result is wound counter-clockwise. For concave Polygons, the result is the Minkowski sum of
+// their convex hulls.
+func (p Polygon) MinkowskiSum(other Polygon) Polygon {
+	sums := make([]Vec, 0, len(p)*len(other))
+	for _, a := range p {
+		for _, b := range other {
+			sums = append(sums, a.Add(b))
+		}
+	}
+	return ConvexHull(sums)
+}
+
+// MinkowskiDifference returns the Minkowski difference of this and the other convex Polygon,
+// which is the set of all differences of a point from this Polygon and a point from the other
+// one. The two Polygons overlap exactly when their Minkowski difference contains the origin.
+//
+// The result is wound counter-clockwise. For concave Polygons, the result is the Minkowski
+// difference of their convex hulls.
+func (p Polygon) MinkowskiDifference(other Polygon) Polygon {
+	return p.MinkowskiSum(other.Scaled(ZV, -1))
+}
@@ -0,0 +1,86 @@
+package pixel_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvexHull(t *testing.T) {
+	assert.Nil(t, pixel.ConvexHull(nil))
+	assert.Equal(t, pixel.Polygon{pixel.V(1, 1)}, pixel.ConvexHull([]pixel.Vec{pixel.V(1, 1), pixel.V(1, 1)}))
+	assert.Equal(t,
+		pixel.Polygon{pixel.V(0, 0), pixel.V(3, 3)},
+		pixel.ConvexHull([]pixel.Vec{pixel.V(2, 2), pixel.V(3, 3), pixel.V(0, 0), pixel.V(1, 1)}),
+	)
+
+	var grid []pixel.Vec
+	for x := 0.0; x <= 10; x++ {
+		for y := 0.0; y <= 10; y++ {
+			grid = append(grid, pixel.V(x, y))
+		}
+	}
+	assert.Equal(t, square, pixel.ConvexHull(grid))
+
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 50; i++ {
+		points := make([]pixel.Vec, 1+rnd.Intn(100))
+		for j := range points {
+			points[j] = pixel.V(rnd.NormFloat64()*10, rnd.NormFloat64()*10)
+		}
+		hull := pixel.ConvexHull(points)
+		if len(hull) < 3 {
+			continue
+		}
+		assert.True(t, hull.IsConvex())
+		assert.False(t, hull.Clockwise())
+		for _, v := range points {
+			assert.True(t, hull.Contains(v), "%v isn't in the hull", v)
+		}
+	}
+}
+
+func TestPolygon_MinkowskiSum(t *testing.T) {
+	sum := square.MinkowskiSum(square.Moved(pixel.V(-5, -5)))
+	assert.Equal(t, pixel.Polygon{pixel.V(-5, -5), pixel.V(15, -5), pixel.V(15, 15), pixel.V(-5, 15)}, sum)
+
+	triangle := pixel.Polygon{pixel.V(0, 0), pixel.V(2, 0), pixel.V(0, 2)}
+	// the square grows by the legs of the triangle to the right and up, plus a triangle in the corner
+	assert.InDelta(t, 100+20+20+2, square.MinkowskiSum(triangle).Area(), 1e-9)
+	assert.Equal(t, square.MinkowskiSum(triangle), triangle.MinkowskiSum(square.Reversed()))
+
+	rnd := rand.New(rand.NewSource(0))
+	randomConvex := func() pixel.Polygon {
+		points := make([]pixel.Vec, 3+rnd.Intn(6))
+		center := pixel.V(rnd.Float64()*20, rnd.Float64()*20)
+		for j := range points {
+			points[j] = center.Add(pixel.V(rnd.NormFloat64()*5, rnd.NormFloat64()*5))
+		}
+		return pixel.ConvexHull(points)
+	}
+	for i := 0; i < 100; i++ {
+		a, b := randomConvex(), randomConvex()
+		diff := a.MinkowskiDifference(b)
+		assert.Equal(t, pixel.Collide(a, b).Colliding, diff.Contains(pixel.ZV) && !onBorder(diff, pixel.ZV))
+	}
+}
+
+func onBorder(p pixel.Polygon, u pixel.Vec) bool {
+	for _, e := range p.Edges() {
+		if e.Closest(u).To(u).Len() < 1e-9 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPolygon_MinkowskiSum_Inflate(t *testing.T) {
+	// the agent can't stand anywhere its box would overlap the obstacle
+	agent := pixel.R(-1, -1, 1, 1).Vertices()
+	walls := square.MinkowskiSum(pixel.Polygon(agent[:]))
+	assert.Equal(t, pixel.R(-1, -1, 11, 11), walls.Bounds())
+	assert.InDelta(t, 144, walls.Area(), 1e-9)
+	assert.False(t, walls.Contains(pixel.V(-1.5, 5)))
+}
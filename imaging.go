@@ -0,0 +1,489 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+// Filter is a method of resampling pixels used when PictureData is resized or rotated.
+type Filter int
+
+// Here's the list of all available Filters, from the fastest and blockiest to the slowest and
+// sharpest. FilterNearest keeps pixel art pixely, FilterBilinear is cheap and smooth, FilterBicubic
+// and FilterLanczos preserve more detail when shrinking photos and painted art.
+const (
+	FilterNearest Filter = iota
+	FilterBilinear
+	FilterBicubic
+	FilterLanczos
+)
+
+// kernel returns the weight function of the Filter and the distance from zero beyond which it's
+// zero. FilterNearest has no kernel.
+func (f Filter) kernel() (k func(x float64) float64, support float64) {
+	switch f {
+	case FilterBilinear:
+		return func(x float64) float64 {
+			return math.Max(0, 1-math.Abs(x))
+		}, 1
+	case FilterBicubic:
+		// Catmull-Rom spline
+		return func(x float64) float64 {
+			x = math.Abs(x)
+			switch {
+			case x < 1:
+				return (1.5*x-2.5)*x*x + 1
+			case x < 2:
+				return ((-0.5*x+2.5)*x-4)*x + 2
+			}
+			return 0
+		}, 2
+	case FilterLanczos:
+		sinc := func(x float64) float64 {
+			if x == 0 {
+				return 1
+			}
+			return math.Sin(math.Pi*x) / (math.Pi * x)
+		}
+		return func(x float64) float64 {
+			if math.Abs(x) >= 3 {
+				return 0
+			}
+			return sinc(x) * sinc(x/3)
+		}, 3
+	}
+	return nil, 0
+}
+
+// checkFilter panics if the Filter is not one of the available Filters.
+func checkFilter(method string, f Filter) {
+	if f < FilterNearest || f > FilterLanczos {
+		panic(fmt.Errorf("(*PictureData).%s: invalid Filter %d", method, f))
+	}
+}
+
+// rgbaImage is an image of RGBA colors, laid out just like the pixels of PictureData. It holds
+// the intermediate results of the image operations, so that they don't lose precision.
+type rgbaImage struct {
+	w, h int
+	pix  []RGBA
+}
+
+func makeRGBAImage(w, h int) rgbaImage {
+	return rgbaImage{w: w, h: h, pix: make([]RGBA, w*h)}
+}
+
+// rgbaImage converts the pixels of the PictureData to an rgbaImage.
+func (pd *PictureData) rgbaImage() rgbaImage {
+	var img rgbaImage
+	img.w = pd.Stride
+	if pd.Stride > 0 {
+		img.h = len(pd.Pix) / pd.Stride
+	}
+	img.pix = make([]RGBA, len(pd.Pix))
+	for i := range pd.Pix {
+		img.pix[i] = ToRGBA(pd.Pix[i])
+	}
+	return img
+}
+
+// at returns the pixel at the given coordinates. The coordinates outside of the image are clamped
+// to its edges.
+func (img rgbaImage) at(x, y int) RGBA {
+	if x < 0 {
+		x = 0
+	}
+	if x >= img.w {
+		x = img.w - 1
+	}
+	if y < 0 {
+		y = 0
+	}
+	if y >= img.h {
+		y = img.h - 1
+	}
+	return img.pix[y*img.w+x]
+}
+
+// pictureData converts the rgbaImage to a PictureData with the given rectangle, which must have
+// the same size in pixels as the rgbaImage.
+func (img rgbaImage) pictureData(rect Rect) *PictureData {
+	pd := MakePictureData(rect)
+	for i, c := range img.pix {
+		// ringing of the sharper filters can push the colors out of the valid range
+		c.A = Clamp(c.A, 0, 1)
+		c.R = Clamp(c.R, 0, c.A)
+		c.G = Clamp(c.G, 0, c.A)
+		c.B = Clamp(c.B, 0, c.A)
+		pd.Pix[i] = toColorRGBA(c)
+	}
+	return pd
+}
+
+// size returns the width and the height of the PictureData in pixels.
+func (pd *PictureData) size() (w, h int) {
+	if pd.Stride == 0 {
+		return 0, 0
+	}
+	return pd.Stride, len(pd.Pix) / pd.Stride
+}
+
+// Cropped returns a copy of the part of the PictureData inside the given Rect. The Rect is
+// limited to the bounds of the PictureData.
+//
+//   frame := sheet.Cropped(pixel.R(32, 0, 64, 32))
+func (pd *PictureData) Cropped(r Rect) *PictureData {
+	r = r.Norm().Intersect(pd.Rect)
+	cropped := MakePictureData(r)
+	w, _ := cropped.size()
+	origin := r.Min.Map(math.Floor)
+	for i := range cropped.Pix {
+		at := origin.Add(V(float64(i%w)+0.5, float64(i/w)+0.5))
+		cropped.Pix[i] = pd.Pix[pd.Index(at)]
+	}
+	return cropped
+}
+
+// FlippedX returns a copy of the PictureData mirrored horizontally, that is, with the left and
+// the right side swapped.
+func (pd *PictureData) FlippedX() *PictureData {
+	w, h := pd.size()
+	flipped := MakePictureData(pd.Rect)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			flipped.Pix[y*w+x] = pd.Pix[y*pd.Stride+w-1-x]
+		}
+	}
+	return flipped
+}
+
+// FlippedY returns a copy of the PictureData mirrored vertically, that is, with the top and the
+// bottom swapped.
+func (pd *PictureData) FlippedY() *PictureData {
+	w, h := pd.size()
+	flipped := MakePictureData(pd.Rect)
+	for y := 0; y < h; y++ {
+		copy(flipped.Pix[y*w:(y+1)*w], pd.Pix[(h-1-y)*pd.Stride:])
+	}
+	return flipped
+}
+
+// Rotated90 returns a copy of the PictureData rotated counter-clockwise by the given number of
+// quarter turns. Negative turns rotate clockwise. The rotation is exact, no pixels are resampled.
+//
+// The result has the same Min corner as the PictureData, only its width and height are swapped
+// after an odd number of turns.
+func (pd *PictureData) Rotated90(turns int) *PictureData {
+	turns = ((turns % 4) + 4) % 4
+	w, h := pd.size()
+	rw, rh := w, h
+	if turns%2 == 1 {
+		rw, rh = h, w
+	}
+	min := pd.Rect.Min.Map(math.Floor)
+	rotated := MakePictureData(Rect{Min: min, Max: min.Add(V(float64(rw), float64(rh)))})
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var rx, ry int
+			switch turns {
+			case 0:
+				rx, ry = x, y
+			case 1:
+				rx, ry = h-1-y, x
+			case 2:
+				rx, ry = w-1-x, h-1-y
+			case 3:
+				rx, ry = y, w-1-x
+			}
+			rotated.Pix[ry*rw+rx] = pd.Pix[y*pd.Stride+x]
+		}
+	}
+	return rotated
+}
+
+// Rotated returns a copy of the PictureData rotated counter-clockwise by the given angle (in
+// radians) around its center, resampled with the Filter. The result is big enough to contain the
+// whole rotated picture and the corners not covered by it are transparent.
+//
+// Use Rotated90 for rotations by right angles, which are exact.
+func (pd *PictureData) Rotated(angle float64, filter Filter) *PictureData {
+	checkFilter("Rotated", filter)
+	src := pd.rgbaImage()
+	w, h := float64(src.w), float64(src.h)
+	sin, cos := math.Sincos(angle)
+	rw := math.Ceil(math.Abs(w*cos)+math.Abs(h*sin) - 1e-9)
+	rh := math.Ceil(math.Abs(w*sin)+math.Abs(h*cos) - 1e-9)
+
+	center := pd.Rect.Min.Map(math.Floor).Add(V(w/2, h/2))
+	min := center.Sub(V(rw/2, rh/2)).Map(math.Floor)
+	dst := makeRGBAImage(int(rw), int(rh))
+
+	// the position in the source image of the center of each pixel, rotated back
+	toSrc := IM.Moved(min.Sub(center)).Rotated(ZV, -angle).Moved(V(w/2, h/2))
+	for y := 0; y < dst.h; y++ {
+		for x := 0; x < dst.w; x++ {
+			at := toSrc.Project(V(float64(x)+0.5, float64(y)+0.5))
+			dst.pix[y*dst.w+x] = src.sample(at, filter)
+		}
+	}
+	return dst.pictureData(Rect{Min: min, Max: min.Add(V(rw, rh))})
+}
+
+// sample returns the color of the image at the given position, where the pixel (x, y) covers the
+// square from (x, y) to (x+1, y+1). Everything outside of the image is transparent.
+func (img rgbaImage) sample(at Vec, filter Filter) RGBA {
+	if filter == FilterNearest {
+		x, y := int(math.Floor(at.X)), int(math.Floor(at.Y))
+		if at.X < 0 || at.Y < 0 || x >= img.w || y >= img.h {
+			return RGBA{}
+		}
+		return img.pix[y*img.w+x]
+	}
+
+	k, support := filter.kernel()
+	cx, cy := at.X-0.5, at.Y-0.5
+	var (
+		sum    RGBA
+		weight float64
+	)
+	for y := int(math.Ceil(cy - support)); y <= int(math.Floor(cy+support)); y++ {
+		wy := k(float64(y) - cy)
+		for x := int(math.Ceil(cx - support)); x <= int(math.Floor(cx+support)); x++ {
+			wxy := wy * k(float64(x)-cx)
+			weight += wxy
+			if x >= 0 && y >= 0 && x < img.w && y < img.h {
+				sum = sum.Add(img.pix[y*img.w+x].Scaled(wxy))
+			}
+		}
+	}
+	if weight == 0 {
+		return RGBA{}
+	}
+	return sum.Scaled(1 / weight)
+}
+
+// Resized returns a copy of the PictureData scaled to the given width and height in pixels,
+// resampled with the Filter. The result has the same Min corner as the PictureData.
+//
+// A zero width or height results in an empty PictureData. Resized panics if the width or the
+// height is negative.
+//
+//   icon := art.Resized(32, 32, pixel.FilterLanczos)
+func (pd *PictureData) Resized(width, height int, filter Filter) *PictureData {
+	if width < 0 || height < 0 {
+		panic(fmt.Errorf("(%T).Resized: negative size %dx%d", pd, width, height))
+	}
+	checkFilter("Resized", filter)
+	min := pd.Rect.Min.Map(math.Floor)
+	rect := Rect{Min: min, Max: min.Add(V(float64(width), float64(height)))}
+	if width == 0 || height == 0 {
+		return MakePictureData(rect)
+	}
+	img := pd.rgbaImage()
+	if img.w > 0 && img.h > 0 {
+		img = img.resampled(width, filter, true)
+		img = img.resampled(height, filter, false)
+	} else {
+		img = makeRGBAImage(width, height)
+	}
+	return img.pictureData(rect)
+}
+
+// resampled returns the image resampled to the given number of pixels in one of the axes.
+func (img rgbaImage) resampled(n int, filter Filter, horizontal bool) rgbaImage {
+	srcLen, otherLen := img.h, img.w
+	if horizontal {
+		srcLen, otherLen = img.w, img.h
+	}
+	scale := float64(srcLen) / float64(n)
+	k, support := filter.kernel()
+	// when shrinking, the kernel is stretched to cover all of the source pixels
+	stretch := math.Max(scale, 1)
+
+	weights := make([]float64, 0, int(2*support*stretch)+2)
+	line := make([]RGBA, n)
+	var dst rgbaImage
+	if horizontal {
+		dst = makeRGBAImage(n, img.h)
+	} else {
+		dst = makeRGBAImage(img.w, n)
+	}
+	pixel := func(i, j int) RGBA {
+		if horizontal {
+			return img.at(i, j)
+		}
+		return img.at(j, i)
+	}
+
+	for j := 0; j < otherLen; j++ {
+		for i := range line {
+			center := (float64(i)+0.5)*scale - 0.5
+			if filter == FilterNearest {
+				line[i] = pixel(int(math.Floor(center+0.5)), j)
+				continue
+			}
+			start := int(math.Ceil(center - support*stretch))
+			end := int(math.Floor(center + support*stretch))
+			weights = weights[:0]
+			var total float64
+			for s := start; s <= end; s++ {
+				w := k((float64(s) - center) / stretch)
+				weights = append(weights, w)
+				total += w
+			}
+			var sum RGBA
+			for s := start; s <= end; s++ {
+				sum = sum.Add(pixel(s, j).Scaled(weights[s-start] / total))
+			}
+			line[i] = sum
+		}
+		for i, c := range line {
+			if horizontal {
+				dst.pix[j*dst.w+i] = c
+			} else {
+				dst.pix[i*dst.w+j] = c
+			}
+		}
+	}
+	return dst
+}
+
+// Convolved returns a copy of the PictureData convolved with the kernel. The kernel is a matrix of
+// weights, its rows go from the top to the bottom, just like they're usually written, and the
+// center of the kernel lies on the pixel being computed. Pixels beyond the edges of the
+// PictureData are taken to be the same as the nearest pixel on the edge.
+//
+// The weights are not normalized, so kernels for edge detection and such work as expected.
+//
+// Convolved panics if the kernel's rows have different lengths or if its width or height is even.
+//
+//   sharpened := pd.Convolved([][]float64{
+//       {0, -1, 0},
+//       {-1, 5, -1},
+//       {0, -1, 0},
+//   })
+func (pd *PictureData) Convolved(kernel [][]float64) *PictureData {
+	kh := len(kernel)
+	if kh%2 == 0 {
+		panic(fmt.Errorf("(%T).Convolved: kernel height must be odd", pd))
+	}
+	kw := len(kernel[0])
+	for _, row := range kernel {
+		if len(row) != kw {
+			panic(fmt.Errorf("(%T).Convolved: kernel rows must have the same length", pd))
+		}
+	}
+	if kw%2 == 0 {
+		panic(fmt.Errorf("(%T).Convolved: kernel width must be odd", pd))
+	}
+
+	src := pd.rgbaImage()
+	dst := makeRGBAImage(src.w, src.h)
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.w; x++ {
+			var sum RGBA
+			for i, row := range kernel {
+				for j, weight := range row {
+					// the first row of the kernel is the top one, which is up the y-axis
+					sum = sum.Add(src.at(x+j-kw/2, y+kh/2-i).Scaled(weight))
+				}
+			}
+			dst.pix[y*dst.w+x] = sum
+		}
+	}
+	return dst.pictureData(pd.Rect)
+}
+
+// Blurred returns a copy of the PictureData with the Gaussian blur of the given standard deviation
+// (in pixels) applied. A non-positive standard deviation leaves the pixels as they are.
+func (pd *PictureData) Blurred(sigma float64) *PictureData {
+	img := pd.rgbaImage()
+	if sigma > 0 {
+		radius := int(math.Ceil(3 * sigma))
+		weights := make([]float64, 2*radius+1)
+		var total float64
+		for i := range weights {
+			d := float64(i - radius)
+			weights[i] = math.Exp(-d * d / (2 * sigma * sigma))
+			total += weights[i]
+		}
+		for i := range weights {
+			weights[i] /= total
+		}
+		img = img.convolved1D(weights, true)
+		img = img.convolved1D(weights, false)
+	}
+	return img.pictureData(pd.Rect)
+}
+
+// convolved1D returns the image convolved with the centered weights along one of the axes.
+func (img rgbaImage) convolved1D(weights []float64, horizontal bool) rgbaImage {
+	dst := makeRGBAImage(img.w, img.h)
+	radius := len(weights) / 2
+	for y := 0; y < img.h; y++ {
+		for x := 0; x < img.w; x++ {
+			var sum RGBA
+			for i, weight := range weights {
+				if horizontal {
+					sum = sum.Add(img.at(x+i-radius, y).Scaled(weight))
+				} else {
+					sum = sum.Add(img.at(x, y+i-radius).Scaled(weight))
+				}
+			}
+			dst.pix[y*dst.w+x] = sum
+		}
+	}
+	return dst
+}
+
+// mapColors returns a copy of the PictureData with the function applied to the straight (not
+// alpha-premultiplied) colors of all of its pixels. The alpha of the pixels is preserved.
+func (pd *PictureData) mapColors(f func(r, g, b float64) (float64, float64, float64)) *PictureData {
+	mapped := MakePictureData(pd.Rect)
+	for i, pix := range pd.Pix {
+		c := ToRGBA(pix)
+		r, g, b := f(c.unpremultiplied())
+		mapped.Pix[i] = toColorRGBA(withAlpha(Clamp(r, 0, 1), Clamp(g, 0, 1), Clamp(b, 0, 1), c.A))
+	}
+	return mapped
+}
+
+// Brightened returns a copy of the PictureData with the amount added to all color components.
+// Positive amounts brighten and negative amounts darken, 1 makes everything white.
+func (pd *PictureData) Brightened(amount float64) *PictureData {
+	return pd.mapColors(func(r, g, b float64) (float64, float64, float64) {
+		return r + amount, g + amount, b + amount
+	})
+}
+
+// Contrasted returns a copy of the PictureData with the contrast multiplied by the factor. The
+// factor of 1 changes nothing, 0 makes everything medium gray and factors above 1 increase the
+// contrast.
+func (pd *PictureData) Contrasted(factor float64) *PictureData {
+	return pd.mapColors(func(r, g, b float64) (float64, float64, float64) {
+		return (r-0.5)*factor + 0.5, (g-0.5)*factor + 0.5, (b-0.5)*factor + 0.5
+	})
+}
+
+// Saturated returns a copy of the PictureData with the saturation multiplied by the factor. The
+// factor of 1 changes nothing, 0 makes the picture grayscale and factors above 1 make the colors
+// more vivid.
+func (pd *PictureData) Saturated(factor float64) *PictureData {
+	return pd.mapColors(func(r, g, b float64) (float64, float64, float64) {
+		// Rec. 709 luma
+		gray := 0.2126*r + 0.7152*g + 0.0722*b
+		return gray + (r-gray)*factor, gray + (g-gray)*factor, gray + (b-gray)*factor
+	})
+}
+
+// HueShifted returns a copy of the PictureData with the hues of all colors rotated by the given
+// angle in degrees.
+//
+//   blueSlime := greenSlime.HueShifted(120)
+func (pd *PictureData) HueShifted(degrees float64) *PictureData {
+	return pd.mapColors(func(r, g, b float64) (float64, float64, float64) {
+		h, s, v := RGB(r, g, b).HSV()
+		c := HSV(h+degrees, s, v)
+		return c.R, c.G, c.B
+	})
+}
@@ -0,0 +1,198 @@
+package pixel_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+var allFilters = []pixel.Filter{
+	pixel.FilterNearest,
+	pixel.FilterBilinear,
+	pixel.FilterBicubic,
+	pixel.FilterLanczos,
+}
+
+// gradientPicture returns a PictureData with a distinct opaque color in every pixel.
+func gradientPicture(rect pixel.Rect) *pixel.PictureData {
+	pd := pixel.MakePictureData(rect)
+	for i := range pd.Pix {
+		x, y := i%pd.Stride, i/pd.Stride
+		pd.Pix[i] = color.RGBA{R: uint8(x * 20), G: uint8(y * 20), B: 100, A: 255}
+	}
+	return pd
+}
+
+func TestPictureData_Cropped(t *testing.T) {
+	pd := gradientPicture(pixel.R(10, 20, 16, 24))
+
+	cropped := pd.Cropped(pixel.R(14, 22, 12, 30))
+	assert.Equal(t, pixel.R(12, 22, 14, 24), cropped.Rect)
+	assert.Len(t, cropped.Pix, 4)
+	for _, at := range []pixel.Vec{pixel.V(12, 22), pixel.V(13, 22), pixel.V(12, 23), pixel.V(13, 23)} {
+		assert.Equal(t, pd.Pix[pd.Index(at)], cropped.Pix[cropped.Index(at)], at)
+	}
+
+	assert.Empty(t, pd.Cropped(pixel.R(0, 0, 5, 5)).Pix)
+}
+
+func TestPictureData_Flipped(t *testing.T) {
+	pd := gradientPicture(pixel.R(0, 0, 3, 2))
+
+	fx := pd.FlippedX()
+	assert.Equal(t, pd.Rect, fx.Rect)
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(0, 1))], fx.Pix[fx.Index(pixel.V(2, 1))])
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(1, 0))], fx.Pix[fx.Index(pixel.V(1, 0))])
+
+	fy := pd.FlippedY()
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(0, 1))], fy.Pix[fy.Index(pixel.V(0, 0))])
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(2, 0))], fy.Pix[fy.Index(pixel.V(2, 1))])
+
+	assert.Equal(t, pd, pd.FlippedX().FlippedX())
+	assert.Equal(t, pd, pd.FlippedY().FlippedY())
+}
+
+func TestPictureData_Rotated90(t *testing.T) {
+	pd := gradientPicture(pixel.R(5, 5, 8, 7))
+
+	r := pd.Rotated90(1)
+	assert.Equal(t, pixel.R(5, 5, 7, 8), r.Rect)
+	// the bottom-right corner goes to the top-right corner
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(7, 5))], r.Pix[r.Index(pixel.V(6, 7))])
+	// the bottom-left corner goes to the bottom-right corner
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(5, 5))], r.Pix[r.Index(pixel.V(6, 5))])
+
+	assert.Equal(t, pd.FlippedX().FlippedY(), pd.Rotated90(2))
+	assert.Equal(t, pd.Rotated90(3), pd.Rotated90(-1))
+	assert.Equal(t, pd, pd.Rotated90(1).Rotated90(1).Rotated90(2))
+}
+
+func TestPictureData_Rotated(t *testing.T) {
+	pd := gradientPicture(pixel.R(0, 0, 4, 4))
+
+	for _, f := range allFilters {
+		assert.Equal(t, pd, pd.Rotated(0, f), "filter %d", f)
+	}
+	assert.Equal(t, pd.Rotated90(1), pd.Rotated(math.Pi/2, pixel.FilterNearest))
+
+	r := pd.Rotated(math.Pi/4, pixel.FilterBilinear)
+	assert.InDelta(t, 2, r.Rect.Center().X, 0.5)
+	assert.InDelta(t, 4*math.Sqrt2, r.Rect.W(), 1)
+	// the corners of the bounds aren't covered by the rotated picture
+	assert.Equal(t, uint8(0), r.Pix[0].A)
+	assert.Equal(t, uint8(255), r.Pix[r.Index(r.Rect.Center())].A)
+
+	assert.Panics(t, func() { pd.Rotated(1, pixel.Filter(-1)) })
+}
+
+func TestPictureData_Resized(t *testing.T) {
+	pd := gradientPicture(pixel.R(2, 3, 6, 7))
+
+	for _, f := range allFilters {
+		assert.Equal(t, pd, pd.Resized(4, 4, f), "filter %d", f)
+
+		r := pd.Resized(9, 2, f)
+		assert.Equal(t, pixel.R(2, 3, 11, 5), r.Rect, "filter %d", f)
+		assert.Len(t, r.Pix, 18, "filter %d", f)
+
+		// uniform pictures stay uniform
+		uniform := pixel.MakePictureData(pixel.R(0, 0, 5, 3))
+		for i := range uniform.Pix {
+			uniform.Pix[i] = color.RGBA{R: 40, G: 80, B: 120, A: 200}
+		}
+		for _, pix := range uniform.Resized(7, 2, f).Pix {
+			assert.Equal(t, color.RGBA{R: 40, G: 80, B: 120, A: 200}, pix, "filter %d", f)
+		}
+
+		// zero sizes give empty pictures
+		assert.Equal(t, pixel.R(2, 3, 2, 5), pd.Resized(0, 2, f).Rect, "filter %d", f)
+		assert.Equal(t, pixel.R(2, 3, 5, 3), pd.Resized(3, 0, f).Rect, "filter %d", f)
+		assert.Empty(t, pd.Resized(3, 0, f).Pix, "filter %d", f)
+	}
+
+	// nearest neighbor doubles the pixels
+	big := pd.Resized(8, 8, pixel.FilterNearest)
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(3, 4))], big.Pix[big.Index(pixel.V(4, 5))])
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(3, 4))], big.Pix[big.Index(pixel.V(5, 6))])
+
+	// shrinking averages the pixels, away from the edges at least
+	checker := pixel.MakePictureData(pixel.R(0, 0, 8, 8))
+	for i := range checker.Pix {
+		if (i%8+i/8)%2 == 0 {
+			checker.Pix[i] = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		} else {
+			checker.Pix[i] = color.RGBA{A: 255}
+		}
+	}
+	small := checker.Resized(4, 4, pixel.FilterBilinear)
+	for _, at := range []pixel.Vec{pixel.V(1, 1), pixel.V(2, 1), pixel.V(1, 2), pixel.V(2, 2)} {
+		assert.InDelta(t, 128, small.Pix[small.Index(at)].R, 1, at)
+	}
+
+	assert.Panics(t, func() { pd.Resized(-1, 2, pixel.FilterNearest) })
+}
+
+func TestPictureData_Convolved(t *testing.T) {
+	pd := gradientPicture(pixel.R(0, 0, 3, 3))
+
+	identity := pd.Convolved([][]float64{
+		{0, 0, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	})
+	assert.Equal(t, pd, identity)
+
+	// the top row of the kernel takes the pixel above
+	up := pd.Convolved([][]float64{
+		{0, 1, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	})
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(1, 2))], up.Pix[up.Index(pixel.V(1, 1))])
+	assert.Equal(t, pd.Pix[pd.Index(pixel.V(1, 2))], up.Pix[up.Index(pixel.V(1, 2))])
+
+	assert.Panics(t, func() { pd.Convolved([][]float64{{1, 1}}) })
+	assert.Panics(t, func() { pd.Convolved([][]float64{{1}, {1}}) })
+	assert.Panics(t, func() { pd.Convolved([][]float64{{1, 1, 1}, {1}, {1, 1, 1}}) })
+}
+
+func TestPictureData_Blurred(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 9, 9))
+	pd.Pix[pd.Index(pixel.V(4, 4))] = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	assert.Equal(t, pd, pd.Blurred(0))
+
+	b := pd.Blurred(1)
+	center := b.Pix[b.Index(pixel.V(4, 4))]
+	assert.Less(t, center.A, uint8(255))
+	assert.Equal(t, b.Pix[b.Index(pixel.V(3, 4))], b.Pix[b.Index(pixel.V(5, 4))])
+	assert.Equal(t, b.Pix[b.Index(pixel.V(4, 3))], b.Pix[b.Index(pixel.V(3, 4))])
+	assert.Less(t, b.Pix[b.Index(pixel.V(3, 4))].A, center.A)
+	assert.Equal(t, uint8(0), b.Pix[b.Index(pixel.V(0, 0))].A)
+}
+
+func TestPictureData_Adjustments(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pd.Pix[0] = color.RGBA{R: 255, A: 255}
+	pd.Pix[1] = color.RGBA{R: 50, G: 25, A: 127}
+
+	b := pd.Brightened(0.2)
+	assert.Equal(t, color.RGBA{R: 255, G: 51, B: 51, A: 255}, b.Pix[0])
+	assert.Equal(t, uint8(127), b.Pix[1].A)
+
+	c := pd.Contrasted(0)
+	assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, c.Pix[0])
+	assert.Equal(t, pd, pd.Contrasted(1))
+
+	s := pd.Saturated(0)
+	assert.Equal(t, s.Pix[0].R, s.Pix[0].G)
+	assert.Equal(t, s.Pix[0].R, s.Pix[0].B)
+	assert.Equal(t, uint8(54), s.Pix[0].R)
+
+	h := pd.HueShifted(120)
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, h.Pix[0])
+	assert.Equal(t, pd, pd.HueShifted(360))
+}
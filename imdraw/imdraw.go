@@ -191,13 +191,9 @@ func (imd *IMDraw) Rectangle(thickness float64) {
 	}
 }
 
-// Polygon draws a polygon from the Pushed points. If the thickness is 0, the convex polygon will be
-// filled. Otherwise, an outline of the specified thickness will be drawn. The outline does not have
-// to be convex.
-//
-// Note, that the filled polygon does not have to be strictly convex. The way it's drawn is that a
-// triangle is drawn between each two adjacent points and the first Pushed point. You can use this
-// property to draw certain kinds of concave polygons.
+// Polygon draws a polygon from the Pushed points. If the thickness is 0, the polygon will be
+// filled. Otherwise, an outline of the specified thickness will be drawn. Neither the filled
+// polygon, nor the outline have to be convex, but the filled polygon must not intersect itself.
 func (imd *IMDraw) Polygon(thickness float64) {
 	if thickness == 0 {
 		imd.fillPolygon()
@@ -356,17 +352,21 @@ func (imd *IMDraw) fillPolygon() {
 		return
 	}
 
+	polygon := make(pixel.Polygon, len(points))
+	for i := range points {
+		polygon[i] = points[i].pos
+	}
+	indices := polygon.Triangulate()
+
 	off := imd.tri.Len()
-	imd.tri.SetLen(imd.tri.Len() + 3*(len(points)-2))
-
-	for i, j := 1, off; i+1 < len(points); i, j = i+1, j+3 {
-		for k, p := range [...]int{0, i, i + 1} {
-			tri := &(*imd.tri)[j+k]
-			tri.Position = points[p].pos
-			tri.Color = points[p].col
-			tri.Picture = points[p].pic
-			tri.Intensity = points[p].in
-		}
+	imd.tri.SetLen(imd.tri.Len() + len(indices))
+
+	for i, p := range indices {
+		tri := &(*imd.tri)[off+i]
+		tri.Position = points[p].pos
+		tri.Color = points[p].col
+		tri.Picture = points[p].pic
+		tri.Intensity = points[p].in
 	}
 
 	imd.applyMatrixAndMask(off)
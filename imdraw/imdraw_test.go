@@ -7,6 +7,7 @@ import (
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixeltest"
 )
 
 func BenchmarkPush(b *testing.B) {
@@ -94,3 +95,59 @@ func BenchmarkEllipseOutline(b *testing.B) {
 		})
 	}
 }
+
+func TestIMDraw_Polygon(t *testing.T) {
+	// a concave arrow with a notch at the top, which a triangle fan would fill
+	imd := imdraw.New(nil)
+	imd.Color = pixel.RGB(1, 0, 0)
+	imd.Push(pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(5, 3), pixel.V(0, 10))
+	imd.Polygon(0)
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 10, 10))
+	imd.Draw(pixel.NewRaster(pd))
+
+	for _, test := range []struct {
+		at   pixel.Vec
+		want pixel.RGBA
+	}{
+		{pixel.V(5, 1), pixel.RGB(1, 0, 0)},
+		{pixel.V(0, 8), pixel.RGB(1, 0, 0)},
+		{pixel.V(9, 8), pixel.RGB(1, 0, 0)},
+		{pixel.V(5, 8), pixel.Alpha(0)},
+	} {
+		if got := pd.Color(test.at); got != test.want {
+			t.Errorf("color at %v = %v, want %v", test.at, got, test.want)
+		}
+	}
+}
+
+func TestIMDraw_Golden(t *testing.T) {
+	imd := imdraw.New(nil)
+
+	imd.Color = pixel.RGB(0.8, 0.2, 0.2)
+	imd.Push(pixel.V(4, 4), pixel.V(28, 4), pixel.V(28, 28), pixel.V(16, 12), pixel.V(4, 28))
+	imd.Polygon(0)
+
+	imd.Color = pixel.RGB(0.2, 0.6, 0.2)
+	imd.Push(pixel.V(48, 16))
+	imd.Circle(12, 3)
+
+	imd.Color = pixel.RGB(0.2, 0.2, 0.8)
+	imd.EndShape = imdraw.RoundEndShape
+	imd.Push(pixel.V(70, 6), pixel.V(90, 26))
+	imd.Line(5)
+
+	imd.Color = pixel.RGB(0.8, 0.6, 0)
+	imd.EndShape = imdraw.SharpEndShape
+	imd.Push(pixel.V(16, 44))
+	imd.EllipseArc(pixel.V(12, 6), 0, 4, 2)
+
+	imd.Push(pixel.V(40, 36), pixel.V(60, 52))
+	imd.Rectangle(0)
+
+	pd := pixeltest.Render(pixel.R(0, 0, 96, 56), func(t pixel.ComposeTarget) {
+		t.(*pixel.Raster).Clear(pixel.RGB(1, 1, 1))
+		imd.Draw(t)
+	})
+	pixeltest.AssertGolden(t, "shapes", pd, pixeltest.Options{Tolerance: 2})
+}
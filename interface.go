@@ -26,6 +26,22 @@ type Target interface {
 	MakePicture(Picture) TargetPicture
 }
 
+// MultiPictureTarget is a Target capable of drawing Triangles with several Pictures at once. Each
+// vertex selects its Picture by the TrianglesPictureIndex property.
+type MultiPictureTarget interface {
+	Target
+
+	// MaxPictures returns the maximal number of Pictures drawn at once.
+	MaxPictures() int
+
+	// MakeMultiPicture generates a specialized TargetPicture drawing with all of the provided
+	// Pictures at once. The i-th Picture is used by the vertices with the PictureIndex i.
+	//
+	// The Pictures may be TargetPictures previously generated by MakePicture of the same Target,
+	// which makes this method cheap.
+	MakeMultiPicture(pics []Picture) TargetPicture
+}
+
 // BasicTarget is a Target with additional basic adjustment methods.
 type BasicTarget interface {
 	Target
@@ -111,6 +127,27 @@ type TrianglesClipped interface {
 	ClipRect(i int) (rect Rect, is bool)
 }
 
+// TrianglesPictureIndex specifies Triangles with PictureIndex property, which selects the Picture
+// of a vertex drawn with a picture made by MultiPictureTarget.MakeMultiPicture.
+type TrianglesPictureIndex interface {
+	Triangles
+	PictureIndex(i int) int
+}
+
+// TrianglesIndexed specifies Triangles with an Indices property.
+//
+// The first value returned from Indices method is a list of indices of vertices, where each three
+// indices form a triangle, so vertices shared by several triangles only need to be stored once.
+// The second one specifies if the Triangles are indexed. If they are, only the triangles listed
+// by the indices are drawn and Len is the number of distinct vertices. Otherwise, each three
+// consecutive vertices form a triangle as usual.
+//
+// All of the indices must be lower than Len.
+type TrianglesIndexed interface {
+	Triangles
+	Indices() (indices []int, is bool)
+}
+
 // Picture represents a rectangular area of raster data, such as a color. It has Bounds which
 // specify the rectangle where data is located.
 type Picture interface {
@@ -132,6 +169,16 @@ type TargetPicture interface {
 	Draw(TargetTriangles)
 }
 
+// ReleasablePicture is a TargetPicture holding resources, such as a GPU texture, which can be
+// released explicitly instead of waiting for the garbage collector.
+type ReleasablePicture interface {
+	TargetPicture
+
+	// Release releases the resources of the TargetPicture. The TargetPicture must not be drawn
+	// after calling this method.
+	Release()
+}
+
 // PictureColor specifies Picture with Color property, so that every position inside the Picture's
 // Bounds has a color.
 //
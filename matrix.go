@@ -96,3 +96,113 @@ func (m Matrix) Unproject(u Vec) Vec {
 		(-m[1]*(u.X-m[4]) + m[0]*(u.Y-m[5])) / det,
 	}
 }
+
+// Inverse returns the Matrix which undoes all transformations of this Matrix, so that
+// m.Chained(m.Inverse()) is IM.
+//
+// If the Matrix is singular (it squashes everything onto a line or a point), it has no inverse and
+// the resulting Matrix is full of NaNs and infinities, just like the results of Unproject.
+func (m Matrix) Inverse() Matrix {
+	det := m[0]*m[3] - m[2]*m[1]
+	return Matrix{
+		m[3] / det,
+		-m[1] / det,
+		-m[2] / det,
+		m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det,
+		(m[1]*m[4] - m[0]*m[5]) / det,
+	}
+}
+
+// Transform is a Matrix broken down into its individual parts. A Matrix composed from a Transform
+// first scales everything around the origin, then skews it, then rotates it around the origin and
+// finally moves it by the Position.
+type Transform struct {
+	// Position is the movement applied last.
+	Position Vec
+
+	// Rotation is the angle of the rotation in radians.
+	Rotation float64
+
+	// Scale is the scale factor in each axis. A negative factor flips the axis.
+	Scale Vec
+
+	// Skew is the angle in radians by which the vertical axis is tilted towards the horizontal
+	// one. Zero means no skew.
+	Skew float64
+}
+
+// String returns a string representation of the Transform.
+//
+//   t := pixel.Transform{Position: pixel.V(1, 2), Rotation: 0.5, Scale: pixel.V(2, 2)}
+//   t.String()     // returns "Transform(Vec(1, 2), 0.5, Vec(2, 2), 0)"
+//   fmt.Println(t) // Transform(Vec(1, 2), 0.5, Vec(2, 2), 0)
+func (t Transform) String() string {
+	return fmt.Sprintf("Transform(%v, %v, %v, %v)", t.Position, t.Rotation, t.Scale, t.Skew)
+}
+
+// Compose returns the Matrix made of the parts of the Transform. It is the inverse operation to
+// Matrix.Decompose.
+func (t Transform) Compose() Matrix {
+	sint, cost := math.Sincos(t.Rotation)
+	k := math.Tan(t.Skew)
+	return Matrix{
+		t.Scale.X * cost,
+		t.Scale.X * sint,
+		t.Scale.Y * (k*cost - sint),
+		t.Scale.Y * (k*sint + cost),
+		t.Position.X,
+		t.Position.Y,
+	}
+}
+
+// Decompose breaks the Matrix down into its position, rotation, scale and skew.
+//
+// Any Matrix can be decomposed and composed back, but the parts aren't unique. For example,
+// flipping both axes is the same as rotating by 180 degrees. Decompose keeps the horizontal scale
+// non-negative and expresses any flip by a negative vertical scale.
+//
+//   m := pixel.IM.Scaled(pixel.ZV, 2).Rotated(pixel.ZV, math.Pi/2).Moved(pixel.V(10, 0))
+//   m.Decompose() // Transform(Vec(10, 0), 1.5707963267948966, Vec(2, 2), 0)
+func (m Matrix) Decompose() Transform {
+	t := Transform{Position: V(m[4], m[5])}
+	sx := math.Hypot(m[0], m[1])
+	if sx == 0 {
+		// the horizontal axis collapsed, so the rotation can only be read from the vertical one
+		t.Rotation = math.Atan2(-m[2], m[3])
+		t.Scale = V(0, math.Hypot(m[2], m[3]))
+		return t
+	}
+	t.Rotation = math.Atan2(m[1], m[0])
+	sint, cost := m[1]/sx, m[0]/sx
+	sy := (m[0]*m[3] - m[2]*m[1]) / sx
+	t.Scale = V(sx, sy)
+	if sy != 0 {
+		t.Skew = math.Atan((m[2]*cost + m[3]*sint) / sy)
+	}
+	return t
+}
+
+// Lerp returns the linear interpolation between this Transform and the next one by alpha.
+//
+// The rotation is interpolated along the shorter arc, all other parts are interpolated linearly.
+func (t Transform) Lerp(next Transform, alpha float64) Transform {
+	turn := math.Remainder(next.Rotation-t.Rotation, 2*math.Pi)
+	return Transform{
+		Position: Lerp(t.Position, next.Position, alpha),
+		Rotation: t.Rotation + turn*alpha,
+		Scale:    Lerp(t.Scale, next.Scale, alpha),
+		Skew:     t.Skew + (next.Skew-t.Skew)*alpha,
+	}
+}
+
+// Lerp returns the interpolation between this Matrix and the next one by t.
+//
+// Unlike interpolating the elements of the matrices, which distorts the shapes in between, this
+// function decomposes both matrices, interpolates their parts and composes the result back. That
+// way, rotating objects keep their shape throughout the animation.
+//
+//   pose := idle.Lerp(jump, 0.25)
+func (m Matrix) Lerp(next Matrix, t float64) Matrix {
+	return m.Decompose().Lerp(next.Decompose(), t).Compose()
+}
@@ -147,3 +147,96 @@ func TestMatrix_Unproject(t *testing.T) {
 		assert.True(t, math.IsNaN(unprojected.Y))
 	})
 }
+
+func assertMatrixInDelta(t *testing.T, expected, actual pixel.Matrix, delta float64) {
+	t.Helper()
+	for i := range expected {
+		assert.InDelta(t, expected[i], actual[i], delta, "expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestMatrix_Inverse(t *testing.T) {
+	matrices := []pixel.Matrix{
+		pixel.IM,
+		pixel.IM.Moved(pixel.V(3, -4)),
+		pixel.IM.Scaled(pixel.V(1, 1), 0.5).Rotated(pixel.ZV, 1).Moved(pixel.V(1, 2)),
+		pixel.IM.ScaledXY(pixel.ZV, pixel.V(-2, 3)).Rotated(pixel.V(5, 5), -2),
+		{1, 0.5, 0.25, 2, 7, 8},
+	}
+	for _, m := range matrices {
+		assertMatrixInDelta(t, pixel.IM, m.Chained(m.Inverse()), 1e-12)
+		assertMatrixInDelta(t, pixel.IM, m.Inverse().Chained(m), 1e-12)
+		u := pixel.V(3, 7)
+		assertVecInDelta(t, m.Unproject(u), m.Inverse().Project(u), 1e-12)
+	}
+
+	inv := pixel.Matrix{1, 2, 2, 4, 0, 0}.Inverse()
+	assert.True(t, math.IsInf(inv[0], +1))
+	assert.True(t, math.IsNaN(inv[4]))
+}
+
+func TestMatrix_Decompose(t *testing.T) {
+	tests := []struct {
+		name      string
+		matrix    pixel.Matrix
+		transform pixel.Transform
+	}{
+		{"identity", pixel.IM, pixel.Transform{Scale: pixel.V(1, 1)}},
+		{"moved", pixel.IM.Moved(pixel.V(1, 2)), pixel.Transform{Position: pixel.V(1, 2), Scale: pixel.V(1, 1)}},
+		{
+			"scaled, rotated and moved",
+			pixel.IM.ScaledXY(pixel.ZV, pixel.V(2, 3)).Rotated(pixel.ZV, math.Pi/3).Moved(pixel.V(-1, 5)),
+			pixel.Transform{Position: pixel.V(-1, 5), Rotation: math.Pi / 3, Scale: pixel.V(2, 3)},
+		},
+		{
+			"flipped",
+			pixel.IM.ScaledXY(pixel.ZV, pixel.V(1, -1)),
+			pixel.Transform{Scale: pixel.V(1, -1)},
+		},
+		{
+			"skewed",
+			pixel.Matrix{1, 0, 1, 1, 0, 0},
+			pixel.Transform{Scale: pixel.V(1, 1), Skew: math.Pi / 4},
+		},
+		{
+			"collapsed",
+			pixel.IM.ScaledXY(pixel.ZV, pixel.V(0, 2)).Rotated(pixel.ZV, math.Pi/2),
+			pixel.Transform{Rotation: math.Pi / 2, Scale: pixel.V(0, 2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := tt.matrix.Decompose()
+			assertVecInDelta(t, tt.transform.Position, tr.Position, 1e-12)
+			assert.InDelta(t, tt.transform.Rotation, tr.Rotation, 1e-12)
+			assertVecInDelta(t, tt.transform.Scale, tr.Scale, 1e-12)
+			assert.InDelta(t, tt.transform.Skew, tr.Skew, 1e-12)
+			assertMatrixInDelta(t, tt.matrix, tr.Compose(), 1e-12)
+		})
+	}
+
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		var m pixel.Matrix
+		for j := range m {
+			m[j] = rnd.Float64()*20 - 10
+		}
+		assertMatrixInDelta(t, m, m.Decompose().Compose(), 1e-9)
+	}
+}
+
+func TestMatrix_Lerp(t *testing.T) {
+	a := pixel.IM.Moved(pixel.V(10, 0))
+	b := pixel.IM.Scaled(pixel.ZV, 3).Rotated(pixel.ZV, math.Pi/2).Moved(pixel.V(0, 10))
+
+	assertMatrixInDelta(t, a, a.Lerp(b, 0), 1e-12)
+	assertMatrixInDelta(t, b, a.Lerp(b, 1), 1e-12)
+	assertMatrixInDelta(t, pixel.IM.Scaled(pixel.ZV, 2).Rotated(pixel.ZV, math.Pi/4).Moved(pixel.V(5, 5)), a.Lerp(b, 0.5), 1e-12)
+
+	// the rotation takes the shorter way around
+	c := pixel.IM.Rotated(pixel.ZV, 3)
+	d := pixel.IM.Rotated(pixel.ZV, -3)
+	mid := c.Lerp(d, 0.5).Decompose()
+	assert.InDelta(t, math.Pi, math.Abs(mid.Rotation), 1e-12)
+	assert.InDelta(t, 1, mid.Scale.X, 1e-12)
+}
@@ -0,0 +1,392 @@
+package pixel
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// MultiBatch is a Target that allows for efficient drawing of many objects with different
+// Pictures, such as sprites from several sprite sheets mixed with text.
+//
+// Objects are drawn in the order they were put into the MultiBatch. When the MultiBatch is drawn
+// onto a MultiPictureTarget (such as pixelgl.Canvas), consecutive objects using up to MaxPictures
+// different Pictures are drawn at once. On other Targets, the objects are split into a separate
+// draw for each run of objects with the same Picture.
+//
+//   mb := pixel.NewMultiBatch()
+//   heroSprite.Draw(mb, heroMatrix)     // from one sheet
+//   treeSprite.Draw(mb, treeMatrix)     // from another sheet
+//   label.Draw(mb, labelMatrix)         // text
+//   mb.Draw(win)
+//
+// Note, that MultiBatch caches the results of MakePicture from Targets it's drawn to for each
+// Picture drawn onto it, even after Clear. Just like with Drawer, using a MultiBatch with an
+// unbounded number of Pictures leads to a memory leak.
+type MultiBatch struct {
+	tri  TrianglesData
+	runs []multiBatchRun
+
+	targets map[Target]*multiBatchTarget
+
+	mat Matrix
+	col RGBA
+}
+
+var _ BasicTarget = (*MultiBatch)(nil)
+
+// multiBatchRun is a range of vertices of a MultiBatch drawn with the same Picture.
+type multiBatchRun struct {
+	pic        Picture
+	start, end int
+}
+
+// multiBatchGroup is a range of runs drawn at once.
+type multiBatchGroup struct {
+	runs []multiBatchRun
+	pics []Picture
+}
+
+type multiBatchTarget struct {
+	tris  []TargetTriangles
+	pics  map[Picture]TargetPicture
+	clean bool
+
+	// the groups and their multi-pictures are made for maxPics, if it changes, they're made again
+	groups    []multiBatchGroup
+	multiPics []TargetPicture
+	maxPics   int
+}
+
+// NewMultiBatch creates an empty MultiBatch.
+func NewMultiBatch() *MultiBatch {
+	mb := &MultiBatch{targets: make(map[Target]*multiBatchTarget)}
+	mb.SetMatrix(IM)
+	mb.SetColorMask(Alpha(1))
+	return mb
+}
+
+// Clear removes all objects from the MultiBatch.
+func (mb *MultiBatch) Clear() {
+	mb.tri.SetLen(0)
+	mb.runs = mb.runs[:0]
+	mb.dirty()
+}
+
+// Len returns the number of vertices in the MultiBatch.
+func (mb *MultiBatch) Len() int {
+	return mb.tri.Len()
+}
+
+func (mb *MultiBatch) dirty() {
+	for _, mt := range mb.targets {
+		mt.clean = false
+	}
+}
+
+// Draw draws all objects that are currently in the MultiBatch onto another Target.
+func (mb *MultiBatch) Draw(t Target) {
+	mt := mb.targets[t]
+	if mt == nil {
+		mt = &multiBatchTarget{pics: make(map[Picture]TargetPicture)}
+		mb.targets[t] = mt
+	}
+
+	maxPics := 1
+	multi, isMulti := t.(MultiPictureTarget)
+	if isMulti {
+		maxPics = multi.MaxPictures()
+	}
+	isMulti = isMulti && maxPics > 1
+
+	if !mt.clean || mt.maxPics != maxPics {
+		mt.groups = mb.groups(maxPics)
+		mt.maxPics = maxPics
+		mb.updateTriangles(t, mt, mt.groups, isMulti)
+		if isMulti {
+			mt.updateMultiPictures(multi)
+		}
+		mt.clean = true
+	}
+
+	for i, g := range mt.groups {
+		tris := mt.tris[i]
+		switch {
+		case len(g.pics) == 0:
+			tris.Draw()
+		case isMulti:
+			mt.multiPics[i].Draw(tris)
+		default:
+			mt.picture(t, g.pics[0]).Draw(tris)
+		}
+	}
+}
+
+// updateMultiPictures makes the multi-picture of each group.
+func (mt *multiBatchTarget) updateMultiPictures(multi MultiPictureTarget) {
+	mt.multiPics = mt.multiPics[:0]
+	for _, g := range mt.groups {
+		if len(g.pics) == 0 {
+			mt.multiPics = append(mt.multiPics, nil)
+			continue
+		}
+		pics := make([]Picture, len(g.pics))
+		for j, pic := range g.pics {
+			pics[j] = mt.picture(multi, pic)
+		}
+		mt.multiPics = append(mt.multiPics, multi.MakeMultiPicture(pics))
+	}
+}
+
+// picture returns the Target's Picture made from the Picture, making it if necessary.
+func (mt *multiBatchTarget) picture(t Target, pic Picture) TargetPicture {
+	tp := mt.pics[pic]
+	if tp == nil {
+		tp = t.MakePicture(pic)
+		mt.pics[pic] = tp
+	}
+	return tp
+}
+
+// groups splits the runs into groups using at most maxPics different Pictures. Runs without a
+// Picture join any group.
+func (mb *MultiBatch) groups(maxPics int) []multiBatchGroup {
+	var groups []multiBatchGroup
+	for _, run := range mb.runs {
+		if len(groups) == 0 {
+			groups = append(groups, multiBatchGroup{})
+		}
+		g := &groups[len(groups)-1]
+		if run.pic != nil && pictureIndex(g.pics, run.pic) < 0 {
+			if len(g.pics) == maxPics {
+				groups = append(groups, multiBatchGroup{})
+				g = &groups[len(groups)-1]
+			}
+			g.pics = append(g.pics, run.pic)
+		}
+		g.runs = append(g.runs, run)
+	}
+	return groups
+}
+
+// pictureIndex returns the index of the Picture in the slice, or -1.
+func pictureIndex(pics []Picture, pic Picture) int {
+	for i := range pics {
+		if pics[i] == pic {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateTriangles updates the Target's Triangles of each group, making new ones if necessary.
+func (mb *MultiBatch) updateTriangles(t Target, mt *multiBatchTarget, groups []multiBatchGroup, isMulti bool) {
+	for i, g := range groups {
+		start, end := g.runs[0].start, g.runs[len(g.runs)-1].end
+		var tri Triangles = mb.tri.Slice(start, end)
+		if isMulti {
+			pti := &pictureIndexedTriangles{
+				TrianglesData: tri.(*TrianglesData),
+				indices:       make([]int, end-start),
+			}
+			for _, run := range g.runs {
+				index := 0
+				if run.pic != nil {
+					index = pictureIndex(g.pics, run.pic)
+				}
+				for j := run.start; j < run.end; j++ {
+					pti.indices[j-start] = index
+				}
+			}
+			tri = pti
+		}
+
+		if i < len(mt.tris) {
+			mt.tris[i].SetLen(tri.Len())
+			mt.tris[i].Update(tri)
+		} else {
+			mt.tris = append(mt.tris, t.MakeTriangles(tri))
+		}
+	}
+	mt.tris = mt.tris[:len(groups)]
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (mb *MultiBatch) SetMatrix(m Matrix) {
+	mb.mat = m
+}
+
+// SetColorMask sets a mask color used in the following draws onto the MultiBatch.
+func (mb *MultiBatch) SetColorMask(c color.Color) {
+	if c == nil {
+		mb.col = Alpha(1)
+		return
+	}
+	mb.col = ToRGBA(c)
+}
+
+// MakeTriangles returns a specialized copy of the provided Triangles that draws onto this
+// MultiBatch.
+func (mb *MultiBatch) MakeTriangles(t Triangles) TargetTriangles {
+	return &multiBatchTriangles{
+		tri: t.Copy(),
+		tmp: MakeTrianglesData(t.Len()),
+		dst: mb,
+	}
+}
+
+// MakePicture returns a specialized copy of the provided Picture that draws onto this MultiBatch.
+// Any Picture is accepted.
+func (mb *MultiBatch) MakePicture(p Picture) TargetPicture {
+	return &multiBatchPicture{
+		pic: p,
+		dst: mb,
+	}
+}
+
+func (mb *MultiBatch) transform() (Matrix, RGBA) {
+	return mb.mat, mb.col
+}
+
+func (mb *MultiBatch) add(tri *TrianglesData, pic Picture) {
+	start := mb.tri.Len()
+	mb.tri = append(mb.tri, *tri...)
+	end := mb.tri.Len()
+
+	if n := len(mb.runs); n > 0 && mb.runs[n-1].pic == pic {
+		mb.runs[n-1].end = end
+	} else if end > start {
+		mb.runs = append(mb.runs, multiBatchRun{pic: pic, start: start, end: end})
+	}
+	mb.dirty()
+}
+
+// batchTarget is a Target accumulating the vertices of the objects drawn onto it using
+// multiBatchTriangles and multiBatchPicture.
+type batchTarget interface {
+	Target
+
+	// transform returns the Matrix and the color mask of the following draws.
+	transform() (Matrix, RGBA)
+
+	// add appends the vertices drawn with the Picture, which may be nil.
+	add(tri *TrianglesData, pic Picture)
+}
+
+type multiBatchTriangles struct {
+	tri Triangles
+	tmp *TrianglesData
+	dst batchTarget
+}
+
+func (mt *multiBatchTriangles) Len() int {
+	return mt.tri.Len()
+}
+
+func (mt *multiBatchTriangles) SetLen(len int) {
+	mt.tri.SetLen(len)
+}
+
+func (mt *multiBatchTriangles) Slice(i, j int) Triangles {
+	return &multiBatchTriangles{
+		tri: mt.tri.Slice(i, j),
+		tmp: &TrianglesData{},
+		dst: mt.dst,
+	}
+}
+
+func (mt *multiBatchTriangles) Update(t Triangles) {
+	mt.tri.Update(t)
+}
+
+func (mt *multiBatchTriangles) Copy() Triangles {
+	return &multiBatchTriangles{
+		tri: mt.tri.Copy(),
+		tmp: &TrianglesData{},
+		dst: mt.dst,
+	}
+}
+
+func (mt *multiBatchTriangles) Indices() (indices []int, is bool) {
+	return indicesOf(mt.tri)
+}
+
+func (mt *multiBatchTriangles) draw(pic Picture) {
+	if indices, indexed := indicesOf(mt.tri); indexed {
+		mt.tmp = unindex(mt.tmp, mt.tri, indices)
+	} else {
+		mt.tmp.SetLen(mt.tri.Len())
+		mt.tmp.Update(mt.tri)
+	}
+
+	mat, col := mt.dst.transform()
+	for i := range *mt.tmp {
+		(*mt.tmp)[i].Position = mat.Project((*mt.tmp)[i].Position)
+		(*mt.tmp)[i].Color = col.Mul((*mt.tmp)[i].Color)
+	}
+
+	mt.dst.add(mt.tmp, pic)
+}
+
+func (mt *multiBatchTriangles) Draw() {
+	mt.draw(nil)
+}
+
+type multiBatchPicture struct {
+	pic Picture
+	dst batchTarget
+}
+
+func (mp *multiBatchPicture) Bounds() Rect {
+	return mp.pic.Bounds()
+}
+
+func (mp *multiBatchPicture) Draw(t TargetTriangles) {
+	mt := t.(*multiBatchTriangles)
+	if mp.dst != mt.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different %T", mp, mp.dst))
+	}
+	mt.draw(mp.pic)
+}
+
+// pictureIndexedTriangles are TrianglesData with the PictureIndex property.
+type pictureIndexedTriangles struct {
+	*TrianglesData
+	indices []int
+}
+
+var _ TrianglesPictureIndex = (*pictureIndexedTriangles)(nil)
+
+func (pt *pictureIndexedTriangles) SetLen(length int) {
+	pt.TrianglesData.SetLen(length)
+	if length > len(pt.indices) {
+		pt.indices = append(pt.indices, make([]int, length-len(pt.indices))...)
+	}
+	pt.indices = pt.indices[:length]
+}
+
+func (pt *pictureIndexedTriangles) Slice(i, j int) Triangles {
+	return &pictureIndexedTriangles{
+		TrianglesData: pt.TrianglesData.Slice(i, j).(*TrianglesData),
+		indices:       pt.indices[i:j],
+	}
+}
+
+func (pt *pictureIndexedTriangles) Update(t Triangles) {
+	pt.TrianglesData.Update(t)
+	if t, ok := t.(TrianglesPictureIndex); ok {
+		for i := range pt.indices {
+			pt.indices[i] = t.PictureIndex(i)
+		}
+	}
+}
+
+func (pt *pictureIndexedTriangles) Copy() Triangles {
+	return &pictureIndexedTriangles{
+		TrianglesData: pt.TrianglesData.Copy().(*TrianglesData),
+		indices:       append([]int(nil), pt.indices...),
+	}
+}
+
+func (pt *pictureIndexedTriangles) PictureIndex(i int) int {
+	return pt.indices[i]
+}
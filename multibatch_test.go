@@ -0,0 +1,161 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+// solidPicture returns a 2x2 PictureData filled with a single color.
+func solidPicture(c color.RGBA) *pixel.PictureData {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+	for i := range pd.Pix {
+		pd.Pix[i] = c
+	}
+	return pd
+}
+
+func TestMultiBatch_Raster(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	redSprite := pixel.NewSprite(solidPicture(red), pixel.R(0, 0, 2, 2))
+	blueSprite := pixel.NewSprite(solidPicture(blue), pixel.R(0, 0, 2, 2))
+
+	mb := pixel.NewMultiBatch()
+	redSprite.Draw(mb, pixel.IM.Moved(pixel.V(1, 1)))
+	blueSprite.Draw(mb, pixel.IM.Moved(pixel.V(2, 1)))
+	redSprite.Draw(mb, pixel.IM.Moved(pixel.V(3, 3)))
+	assert.Equal(t, 18, mb.Len())
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	mb.Draw(pixel.NewRaster(pd))
+	for _, tc := range []struct {
+		at   pixel.Vec
+		want color.RGBA
+	}{
+		{pixel.V(0, 0), red},
+		{pixel.V(1, 0), blue}, // drawn over the first red sprite
+		{pixel.V(2, 1), blue},
+		{pixel.V(2, 2), red}, // drawn over the blue sprite
+		{pixel.V(3, 3), red},
+		{pixel.V(0, 3), color.RGBA{}},
+	} {
+		assert.Equal(t, tc.want, pd.Pix[pd.Index(tc.at)], "pixel %v", tc.at)
+	}
+
+	mb.Clear()
+	assert.Equal(t, 0, mb.Len())
+	pd = pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	mb.Draw(pixel.NewRaster(pd))
+	assert.Equal(t, color.RGBA{}, pd.Pix[pd.Index(pixel.V(0, 0))])
+}
+
+// multiTarget is a MultiPictureTarget which records the Pictures and the picture indices of its
+// draws.
+type multiTarget struct {
+	recordingTarget
+	max     int
+	pics    [][]pixel.Picture
+	indices [][]int
+	draws   int // draws without a multi-picture
+	made    int // made multi-pictures
+}
+
+type multiTargetTriangles struct {
+	*pixel.TrianglesData
+	indices []int
+	dst     *multiTarget
+}
+
+func (mt *multiTargetTriangles) Draw() {
+	mt.dst.draws++
+}
+
+type multiTargetPicture struct {
+	pixel.Picture
+	dst  *multiTarget
+	pics []pixel.Picture
+}
+
+func (mp *multiTargetPicture) Draw(t pixel.TargetTriangles) {
+	mp.dst.pics = append(mp.dst.pics, mp.pics)
+	mp.dst.indices = append(mp.dst.indices, t.(*multiTargetTriangles).indices)
+}
+
+func (mt *multiTarget) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	tri := pixel.MakeTrianglesData(t.Len())
+	tri.Update(t)
+	mtt := &multiTargetTriangles{TrianglesData: tri, dst: mt}
+	if t, ok := t.(pixel.TrianglesPictureIndex); ok {
+		for i := 0; i < t.Len(); i++ {
+			mtt.indices = append(mtt.indices, t.PictureIndex(i))
+		}
+	}
+	return mtt
+}
+
+func (mt *multiTarget) MaxPictures() int {
+	return mt.max
+}
+
+func (mt *multiTarget) MakeMultiPicture(pics []pixel.Picture) pixel.TargetPicture {
+	mt.made++
+	return &multiTargetPicture{Picture: pics[0], dst: mt, pics: pics}
+}
+
+func TestMultiBatch_MultiPictureTarget(t *testing.T) {
+	a, b, c := solidPicture(color.RGBA{R: 255, A: 255}), solidPicture(color.RGBA{G: 255, A: 255}),
+		solidPicture(color.RGBA{B: 255, A: 255})
+
+	mb := pixel.NewMultiBatch()
+	for _, pic := range []*pixel.PictureData{a, b, a, c} {
+		pixel.NewSprite(pic, pic.Bounds()).Draw(mb, pixel.IM)
+	}
+
+	mt := &multiTarget{max: 2}
+	mb.Draw(mt)
+	// a and b fit into the first draw, c needs another one
+	if assert.Len(t, mt.pics, 2) {
+		assert.Len(t, mt.pics[0], 2)
+		assert.Len(t, mt.pics[1], 1)
+	}
+	if assert.Len(t, mt.indices, 2) {
+		want := []int{}
+		for _, index := range []int{0, 1, 0} {
+			want = append(want, index, index, index, index, index, index)
+		}
+		assert.Equal(t, want, mt.indices[0])
+		assert.Equal(t, []int{0, 0, 0, 0, 0, 0}, mt.indices[1])
+	}
+}
+
+func TestMultiBatch_MaxPicturesChange(t *testing.T) {
+	a, b, c := solidPicture(color.RGBA{R: 255, A: 255}), solidPicture(color.RGBA{G: 255, A: 255}),
+		solidPicture(color.RGBA{B: 255, A: 255})
+
+	mb := pixel.NewMultiBatch()
+	for _, pic := range []*pixel.PictureData{a, b, a, c} {
+		pixel.NewSprite(pic, pic.Bounds()).Draw(mb, pixel.IM)
+	}
+
+	mt := &multiTarget{max: 2}
+	mb.Draw(mt)
+	assert.Len(t, mt.pics, 2)
+
+	// a single Picture at once, such as a Canvas with a custom shader, draws each run separately
+	mt.max = 1
+	mb.Draw(mt)
+	assert.Len(t, mt.pics, 2)
+	assert.Equal(t, 4, mt.draws)
+
+	// the multi-picture is made once and reused by the following draws
+	mt.max = 3
+	mb.Draw(mt)
+	mb.Draw(mt)
+	if assert.Len(t, mt.pics, 4) {
+		assert.Len(t, mt.pics[2], 3)
+	}
+	assert.Equal(t, 3, mt.made)
+}
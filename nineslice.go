@@ -0,0 +1,229 @@
+package pixel
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// NineSliceMode specifies how the edges and the center of a NineSlice fill their area.
+type NineSliceMode int
+
+// Here's the list of all available NineSliceModes.
+const (
+	// NineSliceStretch stretches the part of the frame over the whole area.
+	NineSliceStretch NineSliceMode = iota
+
+	// NineSliceTile repeats the part of the frame in its original size over the area. The last
+	// tiles are cut off.
+	NineSliceTile
+)
+
+// NineSlice is a drawable frame of a Picture which can be resized without distorting its borders,
+// also known as a nine-patch. It's useful for UI panels, buttons and text bubbles.
+//
+// The frame is divided into nine parts by the border insets. When drawn at a different size, the
+// corners keep their size, the edges are stretched or tiled along their length and the center is
+// stretched or tiled in both directions.
+//
+// Just like Sprite, NineSlice is anchored by its center.
+//
+//   panel := pixel.NewNineSlice(uiPic, pixel.R(0, 0, 48, 48), 16, 16, 16, 16)
+//   panel.SetSize(pixel.V(300, 200))
+//   panel.Draw(win, pixel.IM.Moved(win.Bounds().Center()))
+type NineSlice struct {
+	tri   *TrianglesData
+	d     Drawer
+	frame Rect
+
+	left, right, bottom, top float64
+	size                     Vec
+	edges, center            NineSliceMode
+
+	matrix Matrix
+	mask   RGBA
+}
+
+// NewNineSlice creates a NineSlice from the supplied frame of a Picture with the given border
+// insets. Its size is initially the size of the frame.
+//
+// NewNineSlice panics if the insets are negative or if they don't fit into the frame.
+func NewNineSlice(pic Picture, frame Rect, left, right, bottom, top float64) *NineSlice {
+	frame = frame.Norm()
+	if left < 0 || right < 0 || bottom < 0 || top < 0 {
+		panic(fmt.Errorf("NewNineSlice: negative insets"))
+	}
+	if left+right > frame.W() || bottom+top > frame.H() {
+		panic(fmt.Errorf("NewNineSlice: insets larger than the frame %v", frame))
+	}
+	tri := &TrianglesData{}
+	ns := &NineSlice{
+		tri:    tri,
+		d:      Drawer{Triangles: tri, Picture: pic, Cached: true},
+		frame:  frame,
+		left:   left,
+		right:  right,
+		bottom: bottom,
+		top:    top,
+		size:   frame.Size(),
+		matrix: IM,
+		mask:   Alpha(1),
+	}
+	ns.calcData()
+	return ns
+}
+
+// Picture returns the Picture of the NineSlice.
+func (ns *NineSlice) Picture() Picture {
+	return ns.d.Picture
+}
+
+// Frame returns the frame of the NineSlice.
+func (ns *NineSlice) Frame() Rect {
+	return ns.frame
+}
+
+// SetSize sets the size the NineSlice is drawn at. If the size is smaller than the borders, the
+// borders are scaled down to fit.
+func (ns *NineSlice) SetSize(size Vec) {
+	size = V(math.Abs(size.X), math.Abs(size.Y))
+	if size != ns.size {
+		ns.size = size
+		ns.calcData()
+	}
+}
+
+// Size returns the size the NineSlice is drawn at.
+func (ns *NineSlice) Size() Vec {
+	return ns.size
+}
+
+// SetMode sets how the edges and the center of the NineSlice fill their areas. Both are stretched
+// by default.
+func (ns *NineSlice) SetMode(edges, center NineSliceMode) {
+	if edges != ns.edges || center != ns.center {
+		ns.edges, ns.center = edges, center
+		ns.calcData()
+	}
+}
+
+// Bounds returns the Rect the NineSlice covers when drawn with the identity Matrix.
+func (ns *NineSlice) Bounds() Rect {
+	return Rect{Min: ns.size.Scaled(-0.5), Max: ns.size.Scaled(0.5)}
+}
+
+// Draw draws the NineSlice onto the provided Target. The NineSlice will be transformed by the
+// given Matrix.
+//
+// This method is equivalent to calling DrawColorMask with nil color mask.
+func (ns *NineSlice) Draw(t Target, matrix Matrix) {
+	ns.DrawColorMask(t, matrix, nil)
+}
+
+// DrawColorMask draws the NineSlice onto the provided Target. The NineSlice will be transformed by
+// the given Matrix and all of its color will be multiplied by the given mask.
+//
+// If the mask is nil, a fully opaque white mask will be used, which causes no effect.
+func (ns *NineSlice) DrawColorMask(t Target, matrix Matrix, mask color.Color) {
+	dirty := false
+	if matrix != ns.matrix {
+		ns.matrix = matrix
+		dirty = true
+	}
+	if mask == nil {
+		mask = Alpha(1)
+	}
+	rgba := ToRGBA(mask)
+	if rgba != ns.mask {
+		ns.mask = rgba
+		dirty = true
+	}
+
+	if dirty {
+		ns.calcData()
+	}
+
+	ns.d.Draw(t)
+}
+
+// nineSliceSpan is a part of a row or a column of a NineSlice, mapping an interval of the drawn
+// NineSlice to an interval of the frame.
+type nineSliceSpan struct {
+	dstMin, dstMax float64
+	srcMin, srcMax float64
+}
+
+// nineSliceSpans divides the drawn interval into spans of the frame's interval, either stretching
+// it or tiling it.
+func nineSliceSpans(dstMin, dstMax, srcMin, srcMax float64, mode NineSliceMode) []nineSliceSpan {
+	if dstMax <= dstMin {
+		return nil
+	}
+	tile := srcMax - srcMin
+	if mode == NineSliceStretch || tile <= 0 {
+		return []nineSliceSpan{{dstMin, dstMax, srcMin, srcMax}}
+	}
+	var spans []nineSliceSpan
+	for d := dstMin; d < dstMax; d += tile {
+		l := math.Min(tile, dstMax-d)
+		spans = append(spans, nineSliceSpan{d, d + l, srcMin, srcMin + l})
+	}
+	return spans
+}
+
+func (ns *NineSlice) calcData() {
+	// the borders shrink if they don't fit into the size
+	sx := math.Min(1, ns.size.X/math.Max(ns.left+ns.right, 1e-300))
+	sy := math.Min(1, ns.size.Y/math.Max(ns.bottom+ns.top, 1e-300))
+	var (
+		min = ns.size.Scaled(-0.5)
+		max = ns.size.Scaled(0.5)
+		f   = ns.frame
+
+		// the edges between the nine parts, of the drawn NineSlice and of the frame
+		dstX = [4]float64{min.X, min.X + ns.left*sx, max.X - ns.right*sx, max.X}
+		dstY = [4]float64{min.Y, min.Y + ns.bottom*sy, max.Y - ns.top*sy, max.Y}
+		srcX = [4]float64{f.Min.X, f.Min.X + ns.left, f.Max.X - ns.right, f.Max.X}
+		srcY = [4]float64{f.Min.Y, f.Min.Y + ns.bottom, f.Max.Y - ns.top, f.Max.Y}
+	)
+
+	*ns.tri = (*ns.tri)[:0]
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			modeX, modeY := NineSliceStretch, NineSliceStretch
+			switch {
+			case i == 1 && j == 1:
+				modeX, modeY = ns.center, ns.center
+			case i == 1:
+				modeX = ns.edges
+			case j == 1:
+				modeY = ns.edges
+			}
+			for _, y := range nineSliceSpans(dstY[j], dstY[j+1], srcY[j], srcY[j+1], modeY) {
+				for _, x := range nineSliceSpans(dstX[i], dstX[i+1], srcX[i], srcX[i+1], modeX) {
+					ns.addQuad(x, y)
+				}
+			}
+		}
+	}
+
+	ns.d.Dirty()
+}
+
+// addQuad appends two triangles covering the spans to the TrianglesData.
+func (ns *NineSlice) addQuad(x, y nineSliceSpan) {
+	corners := [4][2]Vec{
+		{V(x.dstMin, y.dstMin), V(x.srcMin, y.srcMin)},
+		{V(x.dstMax, y.dstMin), V(x.srcMax, y.srcMin)},
+		{V(x.dstMax, y.dstMax), V(x.srcMax, y.srcMax)},
+		{V(x.dstMin, y.dstMax), V(x.srcMin, y.srcMax)},
+	}
+	for _, k := range [...]int{0, 1, 2, 0, 2, 3} {
+		v := zeroValueTriangleData
+		v.Position = ns.matrix.Project(corners[k][0])
+		v.Color = ns.mask
+		v.Picture = corners[k][1]
+		v.Intensity = 1
+		*ns.tri = append(*ns.tri, v)
+	}
+}
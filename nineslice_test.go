@@ -0,0 +1,109 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+// ninePicture returns a 6x6 picture with 2 pixel wide red borders and a blue center.
+func ninePicture() *pixel.PictureData {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 6, 6))
+	for i := range pd.Pix {
+		x, y := i%6, i/6
+		if x < 2 || x >= 4 || y < 2 || y >= 4 {
+			pd.Pix[i] = color.RGBA{R: 255, A: 255}
+		} else {
+			pd.Pix[i] = color.RGBA{B: 255, A: 255}
+		}
+	}
+	return pd
+}
+
+func TestNineSlice_Draw(t *testing.T) {
+	ns := pixel.NewNineSlice(ninePicture(), pixel.R(0, 0, 6, 6), 2, 2, 2, 2)
+	assert.Equal(t, pixel.V(6, 6), ns.Size())
+	ns.SetSize(pixel.V(20, 10))
+	assert.Equal(t, pixel.R(-10, -5, 10, 5), ns.Bounds())
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 20, 10))
+	r := pixel.NewRaster(pd)
+	ns.Draw(r, pixel.IM.Moved(pd.Rect.Center()))
+
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	// the borders keep their width
+	for _, at := range []pixel.Vec{pixel.V(0.5, 0.5), pixel.V(1.5, 5), pixel.V(18.5, 5), pixel.V(10, 8.5), pixel.V(10, 1.5)} {
+		assert.Equal(t, red, pd.Pix[pd.Index(at)], at)
+	}
+	for _, at := range []pixel.Vec{pixel.V(2.5, 2.5), pixel.V(10, 5), pixel.V(17.5, 7.5)} {
+		assert.Equal(t, blue, pd.Pix[pd.Index(at)], at)
+	}
+}
+
+func TestNineSlice_Tile(t *testing.T) {
+	ns := pixel.NewNineSlice(ninePicture(), pixel.R(0, 0, 6, 6), 2, 2, 2, 2)
+	ns.SetSize(pixel.V(9, 9))
+
+	countQuads := func() int {
+		tri := &pixel.TrianglesData{}
+		ns.Draw(&recordingTarget{tri: tri}, pixel.IM)
+		return tri.Len() / 6
+	}
+	assert.Equal(t, 9, countQuads())
+
+	// the edges are 5 pixels long, that's 3 tiles of 2 pixels, the center needs 3x3 tiles
+	ns.SetMode(pixel.NineSliceTile, pixel.NineSliceTile)
+	assert.Equal(t, 4+4*3+9, countQuads())
+
+	ns.SetMode(pixel.NineSliceTile, pixel.NineSliceStretch)
+	assert.Equal(t, 4+4*3+1, countQuads())
+}
+
+func TestNineSlice_Shrink(t *testing.T) {
+	ns := pixel.NewNineSlice(ninePicture(), pixel.R(0, 0, 6, 6), 2, 2, 2, 2)
+	ns.SetSize(pixel.V(2, 8))
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 2, 8))
+	ns.Draw(pixel.NewRaster(pd), pixel.IM.Moved(pd.Rect.Center()))
+	// the left and the right border got squeezed to a pixel each, leaving no center
+	for _, at := range []pixel.Vec{pixel.V(0.5, 4), pixel.V(1.5, 4)} {
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, pd.Pix[pd.Index(at)], at)
+	}
+
+	assert.Panics(t, func() { pixel.NewNineSlice(ninePicture(), pixel.R(0, 0, 6, 6), 4, 4, 0, 0) })
+	assert.Panics(t, func() { pixel.NewNineSlice(ninePicture(), pixel.R(0, 0, 6, 6), -1, 0, 0, 0) })
+}
+
+// recordingTarget is a Target which stores the last drawn triangles.
+type recordingTarget struct {
+	tri *pixel.TrianglesData
+}
+
+type recordingTriangles struct {
+	*pixel.TrianglesData
+	dst *recordingTarget
+}
+
+func (rt *recordingTriangles) Draw() {
+	*rt.dst.tri = append((*rt.dst.tri)[:0], *rt.TrianglesData...)
+}
+
+type recordingPicture struct {
+	pixel.Picture
+}
+
+func (rp recordingPicture) Draw(t pixel.TargetTriangles) {
+	t.Draw()
+}
+
+func (rt *recordingTarget) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	tri := pixel.MakeTrianglesData(t.Len())
+	tri.Update(t)
+	return &recordingTriangles{TrianglesData: tri, dst: rt}
+}
+
+func (rt *recordingTarget) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	return recordingPicture{p}
+}
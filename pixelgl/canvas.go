@@ -3,10 +3,13 @@ package pixelgl
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"runtime"
 
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
 	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/pkg/errors"
 )
@@ -14,10 +17,12 @@ import (
 // Canvas is an off-screen rectangular BasicTarget and Picture at the same time, that you can draw
 // onto.
 //
-// It supports TrianglesPosition, TrianglesColor, TrianglesPicture and PictureColor.
+// It supports TrianglesPosition, TrianglesColor, TrianglesPicture and PictureColor. As a
+// MultiPictureTarget, it draws as many Pictures at once as the texture units of the GPU allow.
 type Canvas struct {
-	gf     *GLFrame
-	shader *GLShader
+	gf       *GLFrame
+	shader   *GLShader
+	backdrop *glhf.Frame
 
 	cmp    pixel.ComposeMethod
 	mat    mgl32.Mat3
@@ -27,7 +32,10 @@ type Canvas struct {
 	sprite *pixel.Sprite
 }
 
-var _ pixel.ComposeTarget = (*Canvas)(nil)
+var (
+	_ pixel.ComposeTarget      = (*Canvas)(nil)
+	_ pixel.MultiPictureTarget = (*Canvas)(nil)
+)
 
 // NewCanvas creates a new empty, fully transparent Canvas with given bounds.
 func NewCanvas(bounds pixel.Rect) *Canvas {
@@ -37,7 +45,7 @@ func NewCanvas(bounds pixel.Rect) *Canvas {
 		col: mgl32.Vec4{1, 1, 1, 1},
 	}
 
-	c.shader = NewGLShader(baseCanvasFragmentShader)
+	c.shader = NewGLShader(baseCanvasFragmentShader())
 	c.SetBounds(bounds)
 	return c
 }
@@ -58,7 +66,8 @@ func (c *Canvas) SetFragmentShader(src string) {
 
 // MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Canvas.
 //
-// TrianglesPosition, TrianglesColor and TrianglesPicture are supported.
+// TrianglesPosition, TrianglesColor, TrianglesPicture, TrianglesClipped and TrianglesIndexed are
+// supported.
 func (c *Canvas) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
 	if gt, ok := t.(*GLTriangles); ok {
 		return &canvasTriangles{
@@ -91,7 +100,35 @@ func (c *Canvas) MakePicture(p pixel.Picture) pixel.TargetPicture {
 	return &canvasPicture{
 		GLPicture: NewGLPicture(p),
 		dst:       c,
+		owned:     true,
+	}
+}
+
+// MaxPictures returns the maximal number of Pictures the Canvas draws at once. It's 1 if the
+// Canvas uses a custom fragment shader, which may not know how to sample multiple Pictures.
+func (c *Canvas) MaxPictures() int {
+	if c.shader.fs != baseCanvasFragmentShader() {
+		return 1
+	}
+	return canvasMaxPictures()
+}
+
+// MakeMultiPicture creates a specialized copy of the supplied Pictures that draws them at once
+// onto this Canvas. The Pictures are selected by the TrianglesPictureIndex property.
+//
+// MakeMultiPicture panics if there are more Pictures than MaxPictures.
+func (c *Canvas) MakeMultiPicture(pics []pixel.Picture) pixel.TargetPicture {
+	if len(pics) > c.MaxPictures() {
+		panic(fmt.Errorf("(%T).MakeMultiPicture: %d Pictures, at most %d supported", c, len(pics), c.MaxPictures()))
 	}
+	mp := &canvasMultiPicture{
+		pics: make([]GLPicture, len(pics)),
+		dst:  c,
+	}
+	for i, p := range pics {
+		mp.pics[i] = c.MakePicture(p).(*canvasPicture).GLPicture
+	}
+	return mp
 }
 
 // SetMatrix sets a Matrix that every point will be projected by.
@@ -120,8 +157,17 @@ func (c *Canvas) SetColorMask(col color.Color) {
 	}
 }
 
-// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
-// this Canvas.
+// SetComposeMethod sets a Porter-Duff composition method or a blend mode to be used in the
+// following draws onto this Canvas.
+//
+// The Porter-Duff composition methods, ComposeScreen and ComposePlus use the OpenGL blending. The
+// other blend modes are computed in the fragment shader from a copy of the part of the Canvas
+// covered by the triangles, made before each draw, so they're slower, and triangles overlapping
+// within a single draw blend with the content of the Canvas before the draw, not with each other.
+//
+// A custom fragment shader set by SetFragmentShader only supports the other blend modes if it
+// uses the uBlendMode and uBackdrop uniforms like the default one does. Otherwise drawing with
+// them panics.
 func (c *Canvas) SetComposeMethod(cmp pixel.ComposeMethod) {
 	c.cmp = cmp
 }
@@ -184,8 +230,60 @@ func setBlendFunc(cmp pixel.ComposeMethod) {
 		glhf.BlendFunc(glhf.One, glhf.One)
 	case pixel.ComposeCopy:
 		glhf.BlendFunc(glhf.One, glhf.Zero)
+	case pixel.ComposeScreen:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_COLOR)
 	default:
-		panic(errors.New("Canvas: invalid compose method"))
+		if shaderBlendMode(cmp) == 0 {
+			panic(errors.New("Canvas: invalid compose method"))
+		}
+		// the fragment shader does the blending itself
+		glhf.BlendFunc(glhf.One, glhf.Zero)
+	}
+}
+
+// shaderBlendMode returns the number identifying the blend mode in the fragment shader, or 0 if
+// the ComposeMethod is done by the OpenGL blending.
+func shaderBlendMode(cmp pixel.ComposeMethod) int32 {
+	switch cmp {
+	case pixel.ComposeMultiply:
+		return 1
+	case pixel.ComposeOverlay:
+		return 2
+	case pixel.ComposeDarken:
+		return 3
+	case pixel.ComposeLighten:
+		return 4
+	case pixel.ComposeColorDodge:
+		return 5
+	case pixel.ComposeColorBurn:
+		return 6
+	case pixel.ComposeHardLight:
+		return 7
+	case pixel.ComposeSoftLight:
+		return 8
+	case pixel.ComposeDifference:
+		return 9
+	case pixel.ComposeExclusion:
+		return 10
+	case pixel.ComposeAdd:
+		return 11
+	default:
+		return 0
+	}
+}
+
+// updateBackdrop copies the given rectangle of the frame, in pixels, to the backdrop. The rest of
+// the backdrop is left stale, the fragment shader only reads the pixels covered by the triangles.
+//
+// must be manually called inside mainthread
+func (c *Canvas) updateBackdrop(x, y, w, h int) {
+	frame := c.gf.Frame()
+	fw, fh := frame.Texture().Width(), frame.Texture().Height()
+	if c.backdrop == nil || c.backdrop.Texture().Width() != fw || c.backdrop.Texture().Height() != fh {
+		c.backdrop = glhf.NewFrame(fw, fh, false)
+	}
+	if w > 0 && h > 0 {
+		frame.Blit(c.backdrop, x, y, x+w, y+h, x, y, x+w, y+h)
 	}
 }
 
@@ -279,7 +377,9 @@ type canvasTriangles struct {
 	dst *Canvas
 }
 
-func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
+// draw draws the triangles with the Pictures. If multi is false, there's at most one Picture
+// bound to the texture unit 0, otherwise the Pictures are selected by the PictureIndex property.
+func (ct *canvasTriangles) draw(pics []GLPicture, multi bool) {
 	ct.dst.gf.Dirty()
 
 	// save the current state vars to avoid race condition
@@ -287,10 +387,23 @@ func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
 	smt := ct.dst.smooth
 	mat := ct.dst.mat
 	col := ct.dst.col
+	blendMode := shaderBlendMode(cmp)
+	if blendMode != 0 && !ct.shader.blendModes {
+		panic(errors.New("Canvas: blend mode not supported by the fragment shader, it doesn't use uBlendMode and uBackdrop"))
+	}
+
+	// only the part of the frame under the triangles is copied to the backdrop
+	var bx, by, bw, bh int
+	if blendMode != 0 {
+		bx, by, bw, bh = ct.frameBounds(mat)
+	}
 
 	mainthread.CallNonBlock(func() {
 		ct.dst.setGlhfBounds()
 		setBlendFunc(cmp)
+		if blendMode != 0 {
+			ct.dst.updateBackdrop(bx, by, bw, bh)
+		}
 
 		frame := ct.dst.gf.Frame()
 		shader := ct.shader.s
@@ -300,6 +413,7 @@ func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
 
 		ct.shader.uniformDefaults.transform = mat
 		ct.shader.uniformDefaults.colormask = col
+		ct.shader.uniformDefaults.blendmode = blendMode
 		dstBounds := ct.dst.Bounds()
 		ct.shader.uniformDefaults.bounds = mgl32.Vec4{
 			float32(dstBounds.Min.X),
@@ -308,6 +422,10 @@ func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
 			float32(dstBounds.H()),
 		}
 
+		var bounds pixel.Rect
+		if len(pics) > 0 {
+			bounds = pics[0].Bounds()
+		}
 		bx, by, bw, bh := intBounds(bounds)
 		ct.shader.uniformDefaults.texbounds = mgl32.Vec4{
 			float32(bx),
@@ -316,26 +434,61 @@ func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
 			float32(bh),
 		}
 
+		ct.shader.uniformDefaults.multipicture = 0
+		if multi {
+			ct.shader.uniformDefaults.multipicture = 1
+			for i, pic := range pics {
+				bx, by, bw, bh := intBounds(pic.Bounds())
+				ct.shader.uniformDefaults.multitexbounds[i] = mgl32.Vec4{
+					float32(bx),
+					float32(by),
+					float32(bw),
+					float32(bh),
+				}
+			}
+		}
+
 		for loc, u := range ct.shader.uniforms {
 			ct.shader.s.SetUniformAttr(loc, u.Value())
 		}
 
-		if tex == nil {
-			ct.vs.Begin()
-			ct.vs.Draw()
-			ct.vs.End()
-		} else {
-			tex.Begin()
+		if blendMode != 0 {
+			gl.ActiveTexture(gl.TEXTURE1)
+			ct.dst.backdrop.Texture().Begin()
+			gl.ActiveTexture(gl.TEXTURE0)
+		}
 
+		// the Pictures of a multi-picture draw are bound after the backdrop
+		unit := func(i int) uint32 {
+			if multi {
+				return gl.TEXTURE0 + canvasReservedTextureUnits + uint32(i)
+			}
+			return gl.TEXTURE0
+		}
+		for i, pic := range pics {
+			gl.ActiveTexture(unit(i))
+			tex := pic.Texture()
+			tex.Begin()
 			if tex.Smooth() != smt {
 				tex.SetSmooth(smt)
 			}
+		}
+		gl.ActiveTexture(gl.TEXTURE0)
 
-			ct.vs.Begin()
-			ct.vs.Draw()
-			ct.vs.End()
+		ct.vs.Begin()
+		ct.GLTriangles.draw()
+		ct.vs.End()
 
-			tex.End()
+		for i := len(pics) - 1; i >= 0; i-- {
+			gl.ActiveTexture(unit(i))
+			pics[i].Texture().End()
+		}
+		gl.ActiveTexture(gl.TEXTURE0)
+
+		if blendMode != 0 {
+			gl.ActiveTexture(gl.TEXTURE1)
+			ct.dst.backdrop.Texture().End()
+			gl.ActiveTexture(gl.TEXTURE0)
 		}
 
 		shader.End()
@@ -343,13 +496,54 @@ func (ct *canvasTriangles) draw(tex *glhf.Texture, bounds pixel.Rect) {
 	})
 }
 
+// frameBounds returns the bounding rectangle of the triangles transformed by the matrix, in the
+// pixels of the frame of the Canvas, clipped to the frame.
+func (ct *canvasTriangles) frameBounds(mat mgl32.Mat3) (x, y, w, h int) {
+	if ct.Len() == 0 {
+		return 0, 0, 0, 0
+	}
+	min := ct.dst.Bounds().Min
+	stride := ct.vs.Stride()
+	r := pixel.R(math.Inf(+1), math.Inf(+1), math.Inf(-1), math.Inf(-1))
+	for i := 0; i < len(ct.data); i += stride {
+		p := mat.Mul3x1(mgl32.Vec3{ct.data[i+triPosX], ct.data[i+triPosY], 1})
+		px, py := float64(p[0])-min.X, float64(p[1])-min.Y
+		r.Min.X, r.Min.Y = math.Min(r.Min.X, px), math.Min(r.Min.Y, py)
+		r.Max.X, r.Max.Y = math.Max(r.Max.X, px), math.Max(r.Max.Y, py)
+	}
+	_, _, fw, fh := intBounds(ct.dst.Bounds())
+	r = r.Intersect(pixel.R(0, 0, float64(fw), float64(fh)))
+	return intBounds(r)
+}
+
 func (ct *canvasTriangles) Draw() {
-	ct.draw(nil, pixel.Rect{})
+	ct.draw(nil, false)
 }
 
 type canvasPicture struct {
 	GLPicture
 	dst *Canvas
+
+	// owned is true if the texture was made by the Canvas for this picture
+	owned bool
+}
+
+var _ pixel.ReleasablePicture = (*canvasPicture)(nil)
+
+// Release deletes the texture of the picture if it was made by the Canvas. Pictures already on
+// the GPU, such as other Canvases, are left untouched.
+func (cp *canvasPicture) Release() {
+	if !cp.owned {
+		return
+	}
+	cp.owned = false
+	tex := cp.GLPicture.Texture()
+	// the texture is deleted now, instead of by its finalizer
+	runtime.SetFinalizer(tex, nil)
+	mainthread.CallNonBlock(func() {
+		obj := tex.ID()
+		gl.DeleteTextures(1, &obj)
+	})
 }
 
 func (cp *canvasPicture) Draw(t pixel.TargetTriangles) {
@@ -357,5 +551,30 @@ func (cp *canvasPicture) Draw(t pixel.TargetTriangles) {
 	if cp.dst != ct.dst {
 		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Canvas", cp))
 	}
-	ct.draw(cp.GLPicture.Texture(), cp.GLPicture.Bounds())
+	ct.draw([]GLPicture{cp.GLPicture}, false)
+}
+
+type canvasMultiPicture struct {
+	pics []GLPicture
+	dst  *Canvas
+}
+
+func (mp *canvasMultiPicture) Bounds() pixel.Rect {
+	var bounds pixel.Rect
+	for i, pic := range mp.pics {
+		if i == 0 {
+			bounds = pic.Bounds()
+			continue
+		}
+		bounds = bounds.Union(pic.Bounds())
+	}
+	return bounds
+}
+
+func (mp *canvasMultiPicture) Draw(t pixel.TargetTriangles) {
+	ct := t.(*canvasTriangles)
+	if mp.dst != ct.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Canvas", mp))
+	}
+	ct.draw(mp.pics, true)
 }
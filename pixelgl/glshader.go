@@ -1,8 +1,14 @@
 package pixelgl
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
+	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/pkg/errors"
 )
@@ -17,12 +23,20 @@ type GLShader struct {
 
 	uniforms []gsUniformAttr
 
+	// blendModes is whether the fragment shader uses the uBlendMode and uBackdrop uniforms
+	blendModes bool
+
 	uniformDefaults struct {
 		transform mgl32.Mat3
 		colormask mgl32.Vec4
 		bounds    mgl32.Vec4
 		texbounds mgl32.Vec4
 		cliprect  mgl32.Vec4
+		blendmode int32
+		backdrop  int32
+
+		multipicture   int32
+		multitexbounds []mgl32.Vec4
 	}
 }
 
@@ -39,14 +53,66 @@ const (
 	canvasTexCoords
 	canvasIntensity
 	canvasClip
+	canvasPictureIndex
 )
 
+// canvasReservedTextureUnits is the number of texture units used by a Canvas besides the Pictures
+// of multi-picture draws: the unit 0 for the Picture of ordinary draws and 1 for the backdrop.
+const canvasReservedTextureUnits = 2
+
+var (
+	canvasShaderOnce         sync.Once
+	canvasMaxPicturesCount   int
+	baseCanvasFragmentSource string
+)
+
+// initCanvasShader queries the number of texture units of the GPU and generates the default
+// fragment shader sampling that many Pictures. It blocks on the main thread, so it must not be
+// called from it.
+func initCanvasShader() {
+	canvasShaderOnce.Do(func() {
+		var units int32
+		mainthread.Call(func() {
+			gl.GetIntegerv(gl.MAX_TEXTURE_IMAGE_UNITS, &units)
+		})
+		n := int(units) - canvasReservedTextureUnits
+		if n < 1 {
+			n = 1
+		}
+
+		var cases strings.Builder
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&cases, "\tcase %d: return texture(uTextures[%d], t);\n", i, i)
+		}
+		baseCanvasFragmentSource = strings.NewReplacer(
+			"{{maxPictures}}", strconv.Itoa(n),
+			"{{pictureCases}}", cases.String(),
+		).Replace(baseCanvasFragmentShaderTemplate)
+		canvasMaxPicturesCount = n
+	})
+}
+
+// canvasMaxPictures returns the number of Pictures a Canvas draws at once, which is the number of
+// texture units of the GPU available to the fragment shader without the reserved ones. The
+// Pictures are bound to the texture units following the reserved ones.
+func canvasMaxPictures() int {
+	initCanvasShader()
+	return canvasMaxPicturesCount
+}
+
+// baseCanvasFragmentShader returns the default fragment shader of a Canvas.
+func baseCanvasFragmentShader() string {
+	initCanvasShader()
+	return baseCanvasFragmentSource
+}
+
 var defaultCanvasVertexFormat = glhf.AttrFormat{
-	canvasPosition:  glhf.Attr{Name: "aPosition", Type: glhf.Vec2},
-	canvasColor:     glhf.Attr{Name: "aColor", Type: glhf.Vec4},
-	canvasTexCoords: glhf.Attr{Name: "aTexCoords", Type: glhf.Vec2},
-	canvasIntensity: glhf.Attr{Name: "aIntensity", Type: glhf.Float},
-  canvasClip:      glhf.Attr{Name: "aClipRect", Type: glhf.Vec4},
+	canvasPosition:     glhf.Attr{Name: "aPosition", Type: glhf.Vec2},
+	canvasColor:        glhf.Attr{Name: "aColor", Type: glhf.Vec4},
+	canvasTexCoords:    glhf.Attr{Name: "aTexCoords", Type: glhf.Vec2},
+	canvasIntensity:    glhf.Attr{Name: "aIntensity", Type: glhf.Float},
+	canvasClip:         glhf.Attr{Name: "aClipRect", Type: glhf.Vec4},
+	canvasPictureIndex: glhf.Attr{Name: "aPictureIndex", Type: glhf.Float},
 }
 
 // Sets up a base shader with everything needed for a Pixel
@@ -58,11 +124,21 @@ func NewGLShader(fragmentShader string) *GLShader {
 		vs: baseCanvasVertexShader,
 		fs: fragmentShader,
 	}
+	// the copy of the Canvas for the blend modes is bound to the texture unit 1
+	gs.uniformDefaults.backdrop = 1
 
 	gs.SetUniform("uTransform", &gs.uniformDefaults.transform)
 	gs.SetUniform("uColorMask", &gs.uniformDefaults.colormask)
 	gs.SetUniform("uBounds", &gs.uniformDefaults.bounds)
 	gs.SetUniform("uTexBounds", &gs.uniformDefaults.texbounds)
+	gs.SetUniform("uBlendMode", &gs.uniformDefaults.blendmode)
+	gs.SetUniform("uBackdrop", &gs.uniformDefaults.backdrop)
+	gs.SetUniform("uMultiPicture", &gs.uniformDefaults.multipicture)
+	gs.uniformDefaults.multitexbounds = make([]mgl32.Vec4, canvasMaxPictures())
+	for i := range gs.uniformDefaults.multitexbounds {
+		gs.SetUniform(fmt.Sprintf("uTextures[%d]", i), int32(canvasReservedTextureUnits+i))
+		gs.SetUniform(fmt.Sprintf("uTexBoundsArray[%d]", i), &gs.uniformDefaults.multitexbounds[i])
+	}
 
 	gs.Update()
 
@@ -91,6 +167,9 @@ func (gs *GLShader) Update() {
 		if err != nil {
 			panic(errors.Wrap(err, "failed to create Canvas, there's a bug in the shader"))
 		}
+		// unused uniforms are optimized out and have no location
+		gs.blendModes = gl.GetUniformLocation(shader.ID(), gl.Str("uBlendMode\x00")) >= 0 &&
+			gl.GetUniformLocation(shader.ID(), gl.Str("uBackdrop\x00")) >= 0
 	})
 
 	gs.s = shader
@@ -244,12 +323,14 @@ in vec2  aTexCoords;
 in float aIntensity;
 in vec4  aClipRect;
 in float aIsClipped;
+in float aPictureIndex;
 
 out vec4  vColor;
 out vec2  vTexCoords;
 out float vIntensity;
 out vec2  vPosition;
 out vec4  vClipRect;
+flat out int vPictureIndex;
 
 uniform mat3 uTransform;
 uniform vec4 uBounds;
@@ -264,22 +345,88 @@ void main() {
 	vTexCoords = aTexCoords;
 	vIntensity = aIntensity;
 	vClipRect = aClipRect;
+	vPictureIndex = int(aPictureIndex + 0.5);
 }
 `
 
-var baseCanvasFragmentShader = `
+// baseCanvasFragmentShaderTemplate is the default fragment shader of a Canvas with the number of
+// Pictures and the cases sampling each of them left to be filled in by initCanvasShader.
+var baseCanvasFragmentShaderTemplate = `
 #version 330 core
 
 in vec4  vColor;
 in vec2  vTexCoords;
 in float vIntensity;
 in vec4  vClipRect;
+flat in int vPictureIndex;
 
 out vec4 fragColor;
 
 uniform vec4 uColorMask;
 uniform vec4 uTexBounds;
 uniform sampler2D uTexture;
+uniform int uBlendMode;
+uniform sampler2D uBackdrop;
+uniform int uMultiPicture;
+uniform sampler2D uTextures[{{maxPictures}}];
+uniform vec4 uTexBoundsArray[{{maxPictures}}];
+
+vec4 pictureColor(vec2 coords) {
+	if (uMultiPicture == 0) {
+		return texture(uTexture, (coords - uTexBounds.xy) / uTexBounds.zw);
+	}
+	// samplers can only be indexed by constants
+	vec4 b = uTexBoundsArray[vPictureIndex];
+	vec2 t = (coords - b.xy) / b.zw;
+	switch (vPictureIndex) {
+{{pictureCases}}	}
+	return vec4(0, 0, 0, 0);
+}
+
+float blendChannel(float s, float b) {
+	switch (uBlendMode) {
+	case 1: // multiply
+		return s * b;
+	case 2: // overlay
+		return b <= 0.5 ? s * 2.0 * b : s + (2.0*b - 1.0) - s * (2.0*b - 1.0);
+	case 3: // darken
+		return min(s, b);
+	case 4: // lighten
+		return max(s, b);
+	case 5: // color dodge
+		if (b == 0.0) return 0.0;
+		if (s >= 1.0) return 1.0;
+		return min(1.0, b / (1.0 - s));
+	case 6: // color burn
+		if (b >= 1.0) return 1.0;
+		if (s == 0.0) return 0.0;
+		return 1.0 - min(1.0, (1.0 - b) / s);
+	case 7: // hard light
+		return s <= 0.5 ? b * 2.0 * s : b + (2.0*s - 1.0) - b * (2.0*s - 1.0);
+	case 8: { // soft light
+		if (s <= 0.5) return b - (1.0 - 2.0*s) * b * (1.0 - b);
+		float d = b <= 0.25 ? ((16.0*b - 12.0) * b + 4.0) * b : sqrt(b);
+		return b + (2.0*s - 1.0) * (d - b);
+	}
+	case 9: // difference
+		return abs(s - b);
+	case 10: // exclusion
+		return s + b - 2.0 * s * b;
+	case 11: // add
+		return min(1.0, s + b);
+	}
+	return s;
+}
+
+vec4 blend(vec4 src, vec4 dst) {
+	vec3 s = src.a > 0.0 ? src.rgb / src.a : vec3(0.0);
+	vec3 b = dst.a > 0.0 ? dst.rgb / dst.a : vec3(0.0);
+	vec3 mixed = vec3(blendChannel(s.r, b.r), blendChannel(s.g, b.g), blendChannel(s.b, b.b));
+	return vec4(
+		src.rgb * (1.0 - dst.a) + dst.rgb * (1.0 - src.a) + src.a * dst.a * mixed,
+		src.a + dst.a - src.a * dst.a
+	);
+}
 
 void main() {
 	if ((vClipRect != vec4(0,0,0,0)) && (gl_FragCoord.x < vClipRect.x || gl_FragCoord.y < vClipRect.y || gl_FragCoord.x > vClipRect.z || gl_FragCoord.y > vClipRect.w))
@@ -290,9 +437,12 @@ void main() {
 	} else {
 		fragColor = vec4(0, 0, 0, 0);
 		fragColor += (1 - vIntensity) * vColor;
-		vec2 t = (vTexCoords - uTexBounds.xy) / uTexBounds.zw;
-		fragColor += vIntensity * vColor * texture(uTexture, t);
+		fragColor += vIntensity * vColor * pictureColor(vTexCoords);
 		fragColor *= uColorMask;
 	}
+
+	if (uBlendMode != 0) {
+		fragColor = blend(fragColor, texelFetch(uBackdrop, ivec2(gl_FragCoord.xy), 0));
+	}
 }
 `
@@ -2,20 +2,30 @@ package pixelgl
 
 import (
 	"fmt"
+	"runtime"
 
 	"github.com/faiface/glhf"
 	"github.com/faiface/mainthread"
 	"github.com/faiface/pixel"
+	"github.com/go-gl/gl/v3.3-core/gl"
 )
 
 // GLTriangles are OpenGL triangles implemented using glhf.VertexSlice.
 //
-// Triangles returned from this function support TrianglesPosition, TrianglesColor and
-// TrianglesPicture. If you need to support more, you can "override" SetLen and Update methods.
+// Triangles returned from this function support TrianglesPosition, TrianglesColor,
+// TrianglesPicture, TrianglesClipped, TrianglesIndexed and TrianglesPictureIndex. If you need to
+// support more, you can "override" SetLen and Update methods.
+//
+// Indexed GLTriangles are drawn from an element buffer.
 type GLTriangles struct {
 	vs     *glhf.VertexSlice
 	data   []float32
 	shader *GLShader
+	offset int // index of the first vertex in the vertex array, non-zero for slices
+
+	indices  []int
+	indexed  bool
+	elements *elementBuffer // only accessed from the main thread
 }
 
 var (
@@ -23,8 +33,30 @@ var (
 	_ pixel.TrianglesColor    = (*GLTriangles)(nil)
 	_ pixel.TrianglesPicture  = (*GLTriangles)(nil)
 	_ pixel.TrianglesClipped  = (*GLTriangles)(nil)
+	_ pixel.TrianglesIndexed  = (*GLTriangles)(nil)
+
+	_ pixel.TrianglesPictureIndex = (*GLTriangles)(nil)
 )
 
+// elementBuffer is an OpenGL element buffer holding the indices of GLTriangles.
+type elementBuffer struct {
+	obj uint32
+	len int32
+}
+
+func newElementBuffer() *elementBuffer {
+	eb := &elementBuffer{}
+	gl.GenBuffers(1, &eb.obj)
+	runtime.SetFinalizer(eb, (*elementBuffer).delete)
+	return eb
+}
+
+func (eb *elementBuffer) delete() {
+	mainthread.CallNonBlock(func() {
+		gl.DeleteBuffers(1, &eb.obj)
+	})
+}
+
 // The following is a helper so that the indices of
 // 	each of these items is easier to see/debug.
 const (
@@ -41,6 +73,7 @@ const (
 	triClipMinY
 	triClipMaxX
 	triClipMaxY
+	triPictureIndex
 	trisAttrLen
 )
 
@@ -77,10 +110,17 @@ func (gt *GLTriangles) Len() int {
 	return len(gt.data) / gt.vs.Stride()
 }
 
-// SetLen efficiently resizes GLTriangles to len.
+// SetLen efficiently resizes GLTriangles to len. If they're indexed and shrink, the triangles
+// using the removed vertices are removed as well.
 //
 // Time complexity is amortized O(1).
 func (gt *GLTriangles) SetLen(length int) {
+	if gt.indexed && length < gt.Len() {
+		if kept := sliceIndices(gt.indices, 0, length); len(kept) != len(gt.indices) {
+			gt.SetIndices(kept)
+		}
+	}
+
 	switch {
 	case length > gt.Len():
 		needAppend := length - gt.Len()
@@ -91,6 +131,7 @@ func (gt *GLTriangles) SetLen(length int) {
 				0, 0,
 				0,
 				0, 0, 0, 0,
+				0,
 			)
 		}
 	case length < gt.Len():
@@ -105,13 +146,33 @@ func (gt *GLTriangles) SetLen(length int) {
 	})
 }
 
-// Slice returns a sub-Triangles of this GLTriangles in range [i, j).
+// Slice returns a sub-Triangles of this GLTriangles in range [i, j). If the GLTriangles are
+// indexed, the slice is indexed by the triangles lying completely within the range, with the
+// indices relative to i.
 func (gt *GLTriangles) Slice(i, j int) pixel.Triangles {
-	return &GLTriangles{
+	st := &GLTriangles{
 		vs:     gt.vs.Slice(i, j),
 		data:   gt.data[i*gt.vs.Stride() : j*gt.vs.Stride()],
 		shader: gt.shader,
+		offset: gt.offset + i,
+	}
+	if gt.indexed {
+		st.SetIndices(sliceIndices(gt.indices, i, j))
 	}
+	return st
+}
+
+// sliceIndices returns the triangles of the indices lying completely within [i, j), rebased to
+// start at i.
+func sliceIndices(indices []int, i, j int) []int {
+	sliced := []int{}
+	for k := 0; k+2 < len(indices); k += 3 {
+		a, b, c := indices[k], indices[k+1], indices[k+2]
+		if a >= i && a < j && b >= i && b < j && c >= i && c < j {
+			sliced = append(sliced, a-i, b-i, c-i)
+		}
+	}
+	return sliced
 }
 
 func (gt *GLTriangles) updateData(t pixel.Triangles) {
@@ -184,9 +245,15 @@ func (gt *GLTriangles) updateData(t pixel.Triangles) {
 			gt.data[i*stride+triClipMaxY] = float32(rect.Max.Y)
 		}
 	}
+	if t, ok := t.(pixel.TrianglesPictureIndex); ok {
+		for i := 0; i < length; i++ {
+			gt.data[i*stride+triPictureIndex] = float32(t.PictureIndex(i))
+		}
+	}
 }
 
-// Update copies vertex properties from the supplied Triangles into this GLTriangles.
+// Update copies vertex properties and the indices from the supplied Triangles into this
+// GLTriangles. If the supplied Triangles aren't indexed, the GLTriangles stop being indexed too.
 //
 // The two Triangles (gt and t) must be of the same len.
 func (gt *GLTriangles) Update(t pixel.Triangles) {
@@ -197,6 +264,88 @@ func (gt *GLTriangles) Update(t pixel.Triangles) {
 
 	// Copy the verteces down to the glhf.VertexData
 	gt.CopyVertices()
+
+	var (
+		indices []int
+		indexed bool
+	)
+	if t, ok := t.(pixel.TrianglesIndexed); ok {
+		indices, indexed = t.Indices()
+	}
+	switch {
+	case !indexed && gt.indexed:
+		gt.SetIndices(nil)
+	case indexed && !gt.sameIndices(indices):
+		gt.SetIndices(append([]int{}, indices...))
+	}
+}
+
+// sameIndices returns whether the GLTriangles are indexed by exactly the given indices.
+func (gt *GLTriangles) sameIndices(indices []int) bool {
+	if !gt.indexed || len(gt.indices) != len(indices) {
+		return false
+	}
+	for i := range indices {
+		if gt.indices[i] != indices[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Indices returns the indices of the GLTriangles and whether they're indexed.
+func (gt *GLTriangles) Indices() (indices []int, is bool) {
+	return gt.indices, gt.indexed
+}
+
+// SetIndices makes the GLTriangles indexed by the given indices and copies them down to the
+// element buffer. All of the indices must be lower than Len.
+//
+// If the indices are nil, the GLTriangles stop being indexed and each three consecutive vertices
+// form a triangle again.
+func (gt *GLTriangles) SetIndices(indices []int) {
+	gt.indices, gt.indexed = indices, indices != nil
+	if !gt.indexed {
+		mainthread.CallNonBlock(func() {
+			gt.elements = nil
+		})
+		return
+	}
+
+	// slices share the vertex array with the GLTriangles they were sliced from
+	data := make([]uint32, len(indices))
+	for i, index := range indices {
+		data[i] = uint32(gt.offset + index)
+	}
+	mainthread.CallNonBlock(func() {
+		if gt.elements == nil {
+			gt.elements = newElementBuffer()
+		}
+		gt.elements.len = int32(len(data))
+		if len(data) == 0 {
+			// avoid setting 0 bytes of buffer data
+			return
+		}
+		gt.vs.Begin()
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, gt.elements.obj)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(data)*4, gl.Ptr(data), gl.DYNAMIC_DRAW)
+		gt.vs.End()
+	})
+}
+
+// draw draws the GLTriangles, from the element buffer if they're indexed. The VertexSlice must be
+// bound and this method must be called from the main thread.
+func (gt *GLTriangles) draw() {
+	if gt.elements == nil {
+		gt.vs.Draw()
+		return
+	}
+	if gt.elements.len == 0 {
+		return
+	}
+	// the element buffer binding is a part of the vertex array, which may have been reallocated
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, gt.elements.obj)
+	gl.DrawElements(gl.TRIANGLES, gt.elements.len, gl.UNSIGNED_INT, nil)
 }
 
 // CopyVertices copies the GLTriangle data down to the vertex data.
@@ -293,6 +442,16 @@ func (gt *GLTriangles) ClipRect(i int) (rect pixel.Rect, is bool) {
 	return
 }
 
+// PictureIndex returns the PictureIndex property of the i-th vertex.
+func (gt *GLTriangles) PictureIndex(i int) int {
+	return int(gt.data[gt.index(i, triPictureIndex)])
+}
+
+// SetPictureIndex sets the PictureIndex property of the i-th vertex.
+func (gt *GLTriangles) SetPictureIndex(i int, index int) {
+	gt.data[gt.index(i, triPictureIndex)] = float32(index)
+}
+
 // SetClipRect sets the Clipping rectangle property of the i-th vertex.
 func (gt *GLTriangles) SetClipRect(i int, rect pixel.Rect) {
 	gt.data[gt.index(i, triClipMinX)] = float32(rect.Min.X)
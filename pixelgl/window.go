@@ -464,7 +464,8 @@ func (w *Window) end() {
 // MakeTriangles generates a specialized copy of the supplied Triangles that will draw onto this
 // Window.
 //
-// Window supports TrianglesPosition, TrianglesColor and TrianglesPicture.
+// Window supports TrianglesPosition, TrianglesColor, TrianglesPicture, TrianglesClipped and
+// TrianglesIndexed.
 func (w *Window) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
 	return w.canvas.MakeTriangles(t)
 }
@@ -476,6 +477,19 @@ func (w *Window) MakePicture(p pixel.Picture) pixel.TargetPicture {
 	return w.canvas.MakePicture(p)
 }
 
+// MaxPictures returns the maximal number of Pictures the Window draws at once.
+func (w *Window) MaxPictures() int {
+	return w.canvas.MaxPictures()
+}
+
+// MakeMultiPicture generates a specialized copy of the supplied Pictures that draws them at once
+// onto this Window.
+//
+// MakeMultiPicture panics if there are more Pictures than MaxPictures.
+func (w *Window) MakeMultiPicture(pics []pixel.Picture) pixel.TargetPicture {
+	return w.canvas.MakeMultiPicture(pics)
+}
+
 // SetMatrix sets a Matrix that every point will be projected by.
 func (w *Window) SetMatrix(m pixel.Matrix) {
 	w.canvas.SetMatrix(m)
@@ -486,8 +500,8 @@ func (w *Window) SetColorMask(c color.Color) {
 	w.canvas.SetColorMask(c)
 }
 
-// SetComposeMethod sets a Porter-Duff composition method to be used in the following draws onto
-// this Window.
+// SetComposeMethod sets a Porter-Duff composition method or a blend mode to be used in the
+// following draws onto this Window.
 func (w *Window) SetComposeMethod(cmp pixel.ComposeMethod) {
 	w.canvas.SetComposeMethod(cmp)
 }
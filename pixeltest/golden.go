@@ -0,0 +1,197 @@
+// Package pixeltest implements utilities for testing rendering against golden images, without an
+// OpenGL context.
+//
+// Scenes are rendered in software by a pixel.Raster and compared with PNG golden files stored in
+// the testdata directory of the tested package. When a comparison fails, the rendered image and a
+// diff image highlighting the differing pixels are written next to the golden file.
+//
+// Run the tests with the -update-golden flag to write the rendered images as the new golden files.
+//
+//   func TestHero(t *testing.T) {
+//       pd := pixeltest.Render(pixel.R(0, 0, 64, 64), func(t pixel.ComposeTarget) {
+//           hero.Draw(t, pixel.IM.Moved(pixel.V(32, 32)))
+//       })
+//       pixeltest.AssertGolden(t, "hero", pd, pixeltest.Options{Tolerance: 2})
+//   }
+package pixeltest
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+var update = flag.Bool("update-golden", false, "write the rendered images as the golden files of pixeltest")
+
+// Render creates a Raster with the given bounds, lets the draw function draw onto it and returns
+// the rendered PictureData.
+func Render(bounds pixel.Rect, draw func(t pixel.ComposeTarget)) *pixel.PictureData {
+	pd := pixel.MakePictureData(bounds)
+	draw(pixel.NewRaster(pd))
+	return pd
+}
+
+// Options specify how much a rendered image may differ from a golden image.
+//
+// A pixel matches the golden one if all of its channels are within the Tolerance, or if their
+// perceptual distance is within the MaxDistance. The zero Options require an exact match.
+type Options struct {
+	// Tolerance is the maximal difference of each channel of matching pixels, in range [0, 255].
+	Tolerance uint8
+
+	// MaxDistance is the maximal perceptual distance of matching pixels: the distance of their
+	// colors in OKLab plus the difference of their alphas. The distance of black and white is 1,
+	// barely noticeable differences are around 0.02. Zero disables the perceptual comparison.
+	MaxDistance float64
+
+	// MaxDiffPixels is the number of pixels which are allowed not to match.
+	MaxDiffPixels int
+}
+
+// Distance returns the perceptual distance of two colors: the distance of the colors in OKLab
+// plus the difference of their alphas.
+func Distance(a, b color.Color) float64 {
+	ca, cb := pixel.ToRGBA(a), pixel.ToRGBA(b)
+	la, aa, ba := ca.OKLab()
+	lb, ab, bb := cb.OKLab()
+	return math.Sqrt((la-lb)*(la-lb)+(aa-ab)*(aa-ab)+(ba-bb)*(ba-bb)) + math.Abs(ca.A-cb.A)
+}
+
+// Compare compares the rendered image with the golden one and returns the number of pixels which
+// don't match and a diff image. The diff image shows the matching pixels as faded gray and the
+// ones which don't match as red.
+//
+// If the bounds of the images are different, all of the pixels of the larger bounds are counted
+// as not matching.
+func Compare(golden, rendered *pixel.PictureData, opts Options) (diffPixels int, diff *pixel.PictureData) {
+	if golden.Rect != rendered.Rect {
+		bounds := golden.Rect.Union(rendered.Rect)
+		diff = pixel.MakePictureData(bounds)
+		for i := range diff.Pix {
+			diff.Pix[i] = color.RGBA{R: 255, A: 255}
+		}
+		return len(diff.Pix), diff
+	}
+
+	diff = pixel.MakePictureData(golden.Rect)
+	for i := range golden.Pix {
+		g, r := golden.Pix[i], rendered.Pix[i]
+		if pixelsMatch(g, r, opts) {
+			gray := uint8(128 + (uint16(g.R)+uint16(g.G)+uint16(g.B))/3/2)
+			diff.Pix[i] = color.RGBA{R: gray, G: gray, B: gray, A: 255}
+			continue
+		}
+		diffPixels++
+		diff.Pix[i] = color.RGBA{R: 255, A: 255}
+	}
+	return diffPixels, diff
+}
+
+func pixelsMatch(a, b color.RGBA, opts Options) bool {
+	channel := func(x, y uint8) bool {
+		if x > y {
+			return x-y <= opts.Tolerance
+		}
+		return y-x <= opts.Tolerance
+	}
+	if channel(a.R, b.R) && channel(a.G, b.G) && channel(a.B, b.B) && channel(a.A, b.A) {
+		return true
+	}
+	return opts.MaxDistance > 0 && Distance(a, b) <= opts.MaxDistance
+}
+
+// AssertGolden compares the rendered image with the golden image testdata/<name>.png and reports
+// an error through t if they don't match according to the Options. It returns whether they match.
+//
+// If they don't, the rendered image and the diff image made by Compare are written to
+// testdata/<name>.actual.png and testdata/<name>.diff.png.
+//
+// If the tests are run with the -update-golden flag, the rendered image is written as the golden
+// image instead.
+//
+// PNG stores colors which are not premultiplied by alpha, so semi-transparent pixels may change
+// slightly when written. Render scenes over an opaque background or allow some Tolerance.
+func AssertGolden(t testing.TB, name string, rendered *pixel.PictureData, opts Options) bool {
+	t.Helper()
+
+	path := filepath.Join("testdata", filepath.FromSlash(name))
+	if *update {
+		if err := writePNG(path+".png", rendered); err != nil {
+			t.Errorf("pixeltest: can't update golden image: %v", err)
+			return false
+		}
+		t.Logf("pixeltest: updated golden image %s.png", path)
+		return true
+	}
+
+	golden, err := readPNG(path + ".png")
+	if err != nil {
+		t.Errorf("pixeltest: can't read golden image, run with -update-golden to create it: %v", err)
+		return false
+	}
+
+	// PNG doesn't store the position of the image, only its size matters
+	if golden.Rect.Size() == rendered.Rect.Size() {
+		golden.Rect = rendered.Rect
+	}
+
+	diffPixels, diff := Compare(golden, rendered, opts)
+	if diffPixels <= opts.MaxDiffPixels {
+		return true
+	}
+
+	if golden.Rect != rendered.Rect {
+		t.Errorf("pixeltest: %s: bounds %v don't match golden bounds %v", name, rendered.Rect, golden.Rect)
+	} else {
+		t.Errorf("pixeltest: %s: %d pixels don't match the golden image, %d allowed", name, diffPixels, opts.MaxDiffPixels)
+	}
+	for _, out := range []struct {
+		suffix string
+		pd     *pixel.PictureData
+	}{
+		{".actual.png", rendered},
+		{".diff.png", diff},
+	} {
+		if err := writePNG(path+out.suffix, out.pd); err != nil {
+			t.Errorf("pixeltest: can't write %s image: %v", out.suffix, err)
+			continue
+		}
+		t.Logf("pixeltest: wrote %s%s", path, out.suffix)
+	}
+	return false
+}
+
+func readPNG(path string) (*pixel.PictureData, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return pixel.PictureDataFromImage(img), nil
+}
+
+func writePNG(path string, pd *pixel.PictureData) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, pd.Image()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
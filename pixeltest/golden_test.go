@@ -0,0 +1,95 @@
+package pixeltest_test
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixeltest"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkerboard renders a 4x4 checkerboard of black and white pixels with the top-right pixel
+// changed to the color.
+func checkerboard(topRight color.RGBA) *pixel.PictureData {
+	return pixeltest.Render(pixel.R(0, 0, 4, 4), func(t pixel.ComposeTarget) {
+		pd := t.(*pixel.Raster).PictureData()
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				c := color.RGBA{A: 255}
+				if (x+y)%2 == 0 {
+					c = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+				}
+				pd.Pix[pd.Index(pixel.V(float64(x), float64(y)))] = c
+			}
+		}
+		pd.Pix[pd.Index(pixel.V(3, 3))] = topRight
+	})
+}
+
+func TestCompare(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	golden := checkerboard(white)
+
+	tests := []struct {
+		topRight color.RGBA
+		opts     pixeltest.Options
+		want     int
+	}{
+		{white, pixeltest.Options{}, 0},
+		{color.RGBA{R: 250, G: 255, B: 255, A: 255}, pixeltest.Options{}, 1},
+		{color.RGBA{R: 250, G: 255, B: 255, A: 255}, pixeltest.Options{Tolerance: 5}, 0},
+		{color.RGBA{R: 250, G: 255, B: 255, A: 255}, pixeltest.Options{MaxDistance: 0.02}, 0},
+		{color.RGBA{R: 255, A: 255}, pixeltest.Options{Tolerance: 5, MaxDistance: 0.02}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.topRight, tt.opts), func(t *testing.T) {
+			diffPixels, diff := pixeltest.Compare(golden, checkerboard(tt.topRight), tt.opts)
+			assert.Equal(t, tt.want, diffPixels)
+			red := color.RGBA{R: 255, A: 255}
+			assert.Equal(t, tt.want == 1, diff.Pix[diff.Index(pixel.V(3, 3))] == red)
+			assert.NotEqual(t, red, diff.Pix[diff.Index(pixel.V(0, 0))])
+		})
+	}
+
+	diffPixels, _ := pixeltest.Compare(golden, pixel.MakePictureData(pixel.R(0, 0, 4, 5)), pixeltest.Options{})
+	assert.Equal(t, 20, diffPixels)
+}
+
+func TestDistance(t *testing.T) {
+	black, white := color.RGBA{A: 255}, color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	assert.InDelta(t, 1, pixeltest.Distance(black, white), 1e-3)
+	assert.InDelta(t, 0, pixeltest.Distance(white, white), 1e-9)
+	assert.InDelta(t, 1, pixeltest.Distance(color.RGBA{}, black), 1e-9)
+}
+
+// failT is a testing.TB recording the errors instead of failing.
+type failT struct {
+	testing.TB
+	errors int
+}
+
+func (ft *failT) Errorf(format string, args ...interface{}) {
+	ft.errors++
+}
+
+func (ft *failT) Logf(format string, args ...interface{}) {}
+
+func TestAssertGolden(t *testing.T) {
+	defer os.Remove("testdata/checkerboard.actual.png")
+	defer os.Remove("testdata/checkerboard.diff.png")
+	ft := &failT{TB: t}
+	assert.False(t, pixeltest.AssertGolden(ft, "checkerboard", checkerboard(color.RGBA{A: 255}), pixeltest.Options{}))
+	assert.Equal(t, 1, ft.errors)
+	assert.FileExists(t, "testdata/checkerboard.actual.png")
+	assert.FileExists(t, "testdata/checkerboard.diff.png")
+
+	// the position of the rendered image doesn't matter
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	moved := checkerboard(white)
+	moved.Rect = moved.Rect.Moved(pixel.V(10, 10))
+	pixeltest.AssertGolden(t, "checkerboard", moved, pixeltest.Options{})
+	pixeltest.AssertGolden(t, "checkerboard", checkerboard(white), pixeltest.Options{})
+}
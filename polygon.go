@@ -0,0 +1,355 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Polygon is a 2D polygon defined by an ordered list of its vertices. The last vertex is
+// implicitly connected to the first one, so the vertices shouldn't be repeated at the end.
+//
+// Polygons can be wound either way (clockwise or counter-clockwise) and don't have to be convex:
+//
+//   p := pixel.Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(5, 3), pixel.V(0, 10)}
+//   p.Contains(pixel.V(5, 2)) // true
+//   p.IsConvex()              // false
+type Polygon []Vec
+
+// String returns the string representation of the Polygon.
+//
+//   p := pixel.Polygon{pixel.V(0, 0), pixel.V(1, 0), pixel.V(0, 1)}
+//   p.String()     // returns "Polygon(Vec(0, 0), Vec(1, 0), Vec(0, 1))"
+//   fmt.Println(p) // Polygon(Vec(0, 0), Vec(1, 0), Vec(0, 1))
+func (p Polygon) String() string {
+	vertices := make([]string, len(p))
+	for i, v := range p {
+		vertices[i] = v.String()
+	}
+	return fmt.Sprintf("Polygon(%s)", strings.Join(vertices, ", "))
+}
+
+// Edges returns the lines which make up the edges of the Polygon. The i-th edge goes from the i-th
+// vertex to the next one.
+func (p Polygon) Edges() []Line {
+	edges := make([]Line, len(p))
+	for i := range p {
+		edges[i] = L(p[i], p[(i+1)%len(p)])
+	}
+	return edges
+}
+
+// SignedArea returns the area of the Polygon, which is positive if the Polygon is wound
+// counter-clockwise and negative if it's wound clockwise.
+//
+// The result for a self-intersecting Polygon is the sum of the signed areas of its parts.
+func (p Polygon) SignedArea() float64 {
+	var area float64
+	for i := range p {
+		area += p[i].Cross(p[(i+1)%len(p)])
+	}
+	return area / 2
+}
+
+// Area returns the area of the Polygon regardless of its winding.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// Clockwise returns whether the vertices of the Polygon are wound clockwise.
+func (p Polygon) Clockwise() bool {
+	return p.SignedArea() < 0
+}
+
+// Reversed returns the Polygon with the order of its vertices (and thus its winding) reversed.
+func (p Polygon) Reversed() Polygon {
+	q := make(Polygon, len(p))
+	for i := range p {
+		q[len(p)-1-i] = p[i]
+	}
+	return q
+}
+
+// Centroid returns the center of mass of the Polygon.
+//
+// If the Polygon has zero area, the average of its vertices is returned instead. The centroid of
+// an empty Polygon is ZV.
+func (p Polygon) Centroid() Vec {
+	if len(p) == 0 {
+		return ZV
+	}
+	area := p.SignedArea()
+	if area == 0 {
+		var sum Vec
+		for _, v := range p {
+			sum = sum.Add(v)
+		}
+		return sum.Scaled(1 / float64(len(p)))
+	}
+
+	var c Vec
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		c = c.Add(a.Add(b).Scaled(a.Cross(b)))
+	}
+	return c.Scaled(1 / (6 * area))
+}
+
+// Bounds returns the smallest Rect which contains the whole Polygon. The Rect is normalized.
+func (p Polygon) Bounds() Rect {
+	if len(p) == 0 {
+		return ZR
+	}
+	r := Rect{Min: p[0], Max: p[0]}
+	for _, v := range p[1:] {
+		r.Min = V(math.Min(r.Min.X, v.X), math.Min(r.Min.Y, v.Y))
+		r.Max = V(math.Max(r.Max.X, v.X), math.Max(r.Max.Y, v.Y))
+	}
+	return r
+}
+
+// onBorder returns whether u lies on one of the Polygon's edges.
+func (p Polygon) onBorder(u Vec) bool {
+	for i := range p {
+		if segmentContains(p[i], p[(i+1)%len(p)], u) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains checks whether a vector u is contained within the Polygon (including its border)
+// according to the even-odd rule. That is, a point is inside if a ray cast from it crosses the
+// edges of the Polygon an odd number of times.
+//
+// For simple (not self-intersecting) Polygons, this is the same as ContainsNonZero.
+func (p Polygon) Contains(u Vec) bool {
+	return p.onBorder(u) || p.crossedOddly(u)
+}
+
+// crossedOddly returns whether a horizontal ray cast from u crosses the edges of the Polygon an
+// odd number of times. The result is undefined for points lying on the Polygon's border.
+func (p Polygon) crossedOddly(u Vec) bool {
+	inside := false
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		if (a.Y > u.Y) != (b.Y > u.Y) {
+			x := a.X + (u.Y-a.Y)/(b.Y-a.Y)*(b.X-a.X)
+			if u.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// ContainsNonZero checks whether a vector u is contained within the Polygon (including its
+// border) according to the non-zero rule. That is, a point is inside if the Polygon winds around
+// it at least once.
+func (p Polygon) ContainsNonZero(u Vec) bool {
+	return p.onBorder(u) || p.WindingNumber(u) != 0
+}
+
+// WindingNumber returns the number of times the Polygon winds around a vector u. Each
+// counter-clockwise turn adds one, each clockwise turn subtracts one.
+//
+// The result is undefined for points lying on the Polygon's border.
+func (p Polygon) WindingNumber(u Vec) int {
+	wn := 0
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		side := a.To(b).Cross(a.To(u))
+		if a.Y <= u.Y {
+			if b.Y > u.Y && side > 0 {
+				wn++
+			}
+		} else if b.Y <= u.Y && side < 0 {
+			wn--
+		}
+	}
+	return wn
+}
+
+// IsConvex returns whether the Polygon is convex. Collinear consecutive edges are allowed.
+//
+// Polygons with less than three vertices and self-intersecting Polygons are not convex.
+func (p Polygon) IsConvex() bool {
+	if len(p) < 3 {
+		return false
+	}
+
+	sign := 0.0
+	turning := 0.0
+	for i := range p {
+		a, b, c := p[i], p[(i+1)%len(p)], p[(i+2)%len(p)]
+		ab, bc := a.To(b), b.To(c)
+		cross := ab.Cross(bc)
+		if cross != 0 {
+			if sign*cross < 0 {
+				return false
+			}
+			sign = cross
+		}
+		turning += math.Atan2(cross, ab.Dot(bc))
+	}
+
+	// a self-intersecting polygon, such as a pentagram, turns in one direction, but more than
+	// once around
+	return sign != 0 && math.Abs(math.Abs(turning)-2*math.Pi) < 1e-9
+}
+
+// Moved returns the Polygon moved by the given vector delta.
+func (p Polygon) Moved(delta Vec) Polygon {
+	return p.Transformed(IM.Moved(delta))
+}
+
+// Rotated returns the Polygon rotated around the given point by the given angle in radians.
+func (p Polygon) Rotated(around Vec, angle float64) Polygon {
+	return p.Transformed(IM.Rotated(around, angle))
+}
+
+// Scaled returns the Polygon scaled around the given point by the scale factor.
+func (p Polygon) Scaled(around Vec, scale float64) Polygon {
+	return p.Transformed(IM.Scaled(around, scale))
+}
+
+// ScaledXY returns the Polygon scaled around the given point by the scale factor in each axis
+// respectively.
+func (p Polygon) ScaledXY(around Vec, scale Vec) Polygon {
+	return p.Transformed(IM.ScaledXY(around, scale))
+}
+
+// Transformed returns the Polygon with all of its vertices projected by the given Matrix.
+func (p Polygon) Transformed(m Matrix) Polygon {
+	q := make(Polygon, len(p))
+	for i, v := range p {
+		q[i] = m.Project(v)
+	}
+	return q
+}
+
+// IntersectionPoints returns all the points where the Polygon's edges intersect with the line
+// provided. The points of intersection will be returned in order of closest-to-l.A to
+// closest-to-l.B. Edges overlapping with the line produce no points.
+func (p Polygon) IntersectionPoints(l Line) []Vec {
+	var ts []float64
+	for i := range p {
+		if t, _, ok := segmentIntersection(l.A, l.B, p[i], p[(i+1)%len(p)]); ok {
+			ts = append(ts, t)
+		}
+	}
+
+	// insertion sort, the number of points is usually tiny
+	for i := 1; i < len(ts); i++ {
+		for j := i; j > 0 && ts[j] < ts[j-1]; j-- {
+			ts[j], ts[j-1] = ts[j-1], ts[j]
+		}
+	}
+
+	points := make([]Vec, 0, len(ts))
+	for _, t := range ts {
+		v := Lerp(l.A, l.B, t)
+		// a line passing through a vertex intersects both of its edges
+		if len(points) > 0 && points[len(points)-1].Eq(v) {
+			continue
+		}
+		points = append(points, v)
+	}
+	return points
+}
+
+// IntersectsLine returns whether the Polygon (including its interior) and the line segment have
+// at least one point in common.
+func (p Polygon) IntersectsLine(l Line) bool {
+	if p.Contains(l.A) || p.Contains(l.B) {
+		return true
+	}
+	for i := range p {
+		if segmentsIntersect(l.A, l.B, p[i], p[(i+1)%len(p)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// IntersectsRect returns whether the Polygon and the Rect overlap (including their borders). The
+// Rect must be normalized.
+func (p Polygon) IntersectsRect(r Rect) bool {
+	for _, v := range p {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	for _, v := range r.Vertices() {
+		if p.Contains(v) {
+			return true
+		}
+	}
+	for _, edge := range r.Edges() {
+		for i := range p {
+			if segmentsIntersect(edge.A, edge.B, p[i], p[(i+1)%len(p)]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IntersectsCircle returns whether the Polygon and the Circle overlap (including their borders).
+func (p Polygon) IntersectsCircle(c Circle) bool {
+	c = c.Norm()
+	if p.Contains(c.Center) {
+		return true
+	}
+	for i := range p {
+		closest := segmentClosest(p[i], p[(i+1)%len(p)], c.Center)
+		if closest.To(c.Center).Len() <= c.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentClosest returns the point on the segment between a and b closest to u.
+func segmentClosest(a, b, u Vec) Vec {
+	ab := a.To(b)
+	sqLen := ab.SqLen()
+	if sqLen == 0 {
+		return a
+	}
+	t := Clamp(a.To(u).Dot(ab)/sqLen, 0, 1)
+	return a.Add(ab.Scaled(t))
+}
+
+// segmentContains returns whether u lies on the segment between a and b. The point may be off by a
+// tiny distance relative to the length of the segment, to tolerate rounding errors.
+func segmentContains(a, b, u Vec) bool {
+	epsilon := 1e-9 * math.Max(a.To(b).Len(), 1)
+	return segmentClosest(a, b, u).To(u).SqLen() <= epsilon*epsilon
+}
+
+// segmentIntersection returns the parameters t and s, such that Lerp(a, b, t) and Lerp(c, d, s)
+// are the point where the segments ab and cd intersect. Parallel segments never intersect.
+func segmentIntersection(a, b, c, d Vec) (t, s float64, ok bool) {
+	ab, cd, ac := a.To(b), c.To(d), a.To(c)
+	denom := ab.Cross(cd)
+	if denom == 0 {
+		return 0, 0, false
+	}
+	t = ac.Cross(cd) / denom
+	s = ac.Cross(ab) / denom
+	if t < 0 || t > 1 || s < 0 || s > 1 {
+		return 0, 0, false
+	}
+	return t, s, true
+}
+
+// segmentsIntersect returns whether the segments ab and cd have at least one point in common,
+// including the case of collinear overlapping segments.
+func segmentsIntersect(a, b, c, d Vec) bool {
+	if _, _, ok := segmentIntersection(a, b, c, d); ok {
+		return true
+	}
+	return segmentContains(a, b, c) || segmentContains(a, b, d) ||
+		segmentContains(c, d, a) || segmentContains(c, d, b)
+}
@@ -0,0 +1,139 @@
+package pixel_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// square is a counter-clockwise 10x10 square with its lower-left corner at the origin
+	square = pixel.Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(0, 10)}
+
+	// arrow is a concave, counter-clockwise polygon with a notch at the top
+	arrow = pixel.Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(5, 3), pixel.V(0, 10)}
+
+	// pentagram is a self-intersecting star
+	pentagram = func() pixel.Polygon {
+		var p pixel.Polygon
+		for i := 0; i < 5; i++ {
+			p = append(p, pixel.Unit(math.Pi/2+float64(i)*4*math.Pi/5).Scaled(10))
+		}
+		return p
+	}()
+)
+
+func TestPolygon_Area(t *testing.T) {
+	assert.Equal(t, 100.0, square.SignedArea())
+	assert.Equal(t, -100.0, square.Reversed().SignedArea())
+	assert.Equal(t, 100.0, square.Reversed().Area())
+	assert.Equal(t, 65.0, arrow.Area())
+	assert.False(t, square.Clockwise())
+	assert.True(t, square.Reversed().Clockwise())
+}
+
+func TestPolygon_Centroid(t *testing.T) {
+	assert.Equal(t, pixel.V(5, 5), square.Centroid())
+	assert.Equal(t, pixel.V(5, 5), square.Reversed().Centroid())
+
+	triangle := pixel.Polygon{pixel.V(0, 0), pixel.V(3, 0), pixel.V(0, 3)}
+	assert.True(t, pixel.V(1, 1).Eq(triangle.Centroid()))
+
+	degenerate := pixel.Polygon{pixel.V(0, 0), pixel.V(2, 0), pixel.V(4, 0)}
+	assert.Equal(t, pixel.V(2, 0), degenerate.Centroid())
+
+	assert.Equal(t, pixel.ZV, pixel.Polygon{}.Centroid())
+}
+
+func TestPolygon_Bounds(t *testing.T) {
+	assert.Equal(t, pixel.R(0, 0, 10, 10), arrow.Bounds())
+	assert.Equal(t, pixel.R(-1, -2, 3, 4), pixel.Polygon{pixel.V(3, -2), pixel.V(-1, 4), pixel.V(0, 0)}.Bounds())
+	assert.Equal(t, pixel.ZR, pixel.Polygon{}.Bounds())
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       pixel.Polygon
+		u       pixel.Vec
+		evenOdd bool
+		nonZero bool
+	}{
+		{"inside square", square, pixel.V(5, 5), true, true},
+		{"outside square", square, pixel.V(15, 5), false, false},
+		{"on square edge", square, pixel.V(10, 5), true, true},
+		{"on square vertex", square, pixel.V(0, 0), true, true},
+		{"on diagonal edge", pixel.Polygon{pixel.V(-0.3, -0.1), pixel.V(0.6, 0.2), pixel.V(0, 1)}, pixel.ZV, true, true},
+		{"inside clockwise square", square.Reversed(), pixel.V(5, 5), true, true},
+		{"inside arrow", arrow, pixel.V(5, 2), true, true},
+		{"in arrow notch", arrow, pixel.V(5, 8), false, false},
+		{"pentagram point", pentagram, pixel.V(0, 9), true, true},
+		{"pentagram center", pentagram, pixel.ZV, false, true},
+		{"outside pentagram", pentagram, pixel.V(10, 10), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.evenOdd, tt.p.Contains(tt.u))
+			assert.Equal(t, tt.nonZero, tt.p.ContainsNonZero(tt.u))
+		})
+	}
+}
+
+func TestPolygon_WindingNumber(t *testing.T) {
+	assert.Equal(t, 1, square.WindingNumber(pixel.V(5, 5)))
+	assert.Equal(t, -1, square.Reversed().WindingNumber(pixel.V(5, 5)))
+	assert.Equal(t, 0, square.WindingNumber(pixel.V(50, 5)))
+	assert.Equal(t, 2, pentagram.WindingNumber(pixel.ZV))
+}
+
+func TestPolygon_IsConvex(t *testing.T) {
+	assert.True(t, square.IsConvex())
+	assert.True(t, square.Reversed().IsConvex())
+	assert.True(t, pixel.Polygon{pixel.V(0, 0), pixel.V(5, 0), pixel.V(10, 0), pixel.V(5, 5)}.IsConvex())
+	assert.False(t, arrow.IsConvex())
+	assert.False(t, pentagram.IsConvex())
+	assert.False(t, pixel.Polygon{pixel.V(0, 0), pixel.V(1, 1)}.IsConvex())
+}
+
+func TestPolygon_Transformed(t *testing.T) {
+	assert.Equal(t, pixel.Polygon{pixel.V(1, 2), pixel.V(11, 2), pixel.V(11, 12), pixel.V(1, 12)}, square.Moved(pixel.V(1, 2)))
+	assert.Equal(t, pixel.Polygon{pixel.V(0, 0), pixel.V(20, 0), pixel.V(20, 20), pixel.V(0, 20)}, square.Scaled(pixel.ZV, 2))
+
+	rotated := square.Rotated(pixel.V(5, 5), math.Pi)
+	for i, v := range []pixel.Vec{pixel.V(10, 10), pixel.V(0, 10), pixel.V(0, 0), pixel.V(10, 0)} {
+		assert.InDelta(t, v.X, rotated[i].X, 1e-9)
+		assert.InDelta(t, v.Y, rotated[i].Y, 1e-9)
+	}
+
+	m := pixel.IM.Rotated(pixel.ZV, 1).Moved(pixel.V(3, 4))
+	assert.InDelta(t, square.Area(), square.Transformed(m).Area(), 1e-9)
+}
+
+func TestPolygon_IntersectionPoints(t *testing.T) {
+	assert.Equal(t, []pixel.Vec{pixel.V(0, 5), pixel.V(10, 5)}, square.IntersectionPoints(pixel.L(pixel.V(-5, 5), pixel.V(15, 5))))
+	assert.Equal(t, []pixel.Vec{pixel.V(10, 5), pixel.V(0, 5)}, square.IntersectionPoints(pixel.L(pixel.V(15, 5), pixel.V(-5, 5))))
+	assert.Equal(t, []pixel.Vec{pixel.V(10, 10)}, square.IntersectionPoints(pixel.L(pixel.V(5, 15), pixel.V(15, 5))))
+	assert.Equal(t, []pixel.Vec{pixel.V(5, 10)}, square.IntersectionPoints(pixel.L(pixel.V(5, 5), pixel.V(5, 15))))
+	assert.Empty(t, square.IntersectionPoints(pixel.L(pixel.V(1, 1), pixel.V(2, 2))))
+	assert.Len(t, arrow.IntersectionPoints(pixel.L(pixel.V(-1, 8), pixel.V(11, 8))), 4)
+}
+
+func TestPolygon_Intersects(t *testing.T) {
+	assert.True(t, arrow.IntersectsLine(pixel.L(pixel.V(1, 1), pixel.V(2, 2))))
+	assert.True(t, arrow.IntersectsLine(pixel.L(pixel.V(-1, 8), pixel.V(11, 8))))
+	assert.False(t, arrow.IntersectsLine(pixel.L(pixel.V(4, 9), pixel.V(6, 9))))
+
+	assert.True(t, arrow.IntersectsRect(pixel.R(8, 8, 20, 20)))
+	assert.True(t, arrow.IntersectsRect(pixel.R(-5, -5, 15, 15)))
+	assert.True(t, arrow.IntersectsRect(pixel.R(2, -5, 3, 15)))
+	assert.False(t, arrow.IntersectsRect(pixel.R(4.5, 8, 5.5, 9)))
+	assert.False(t, arrow.IntersectsRect(pixel.R(11, 0, 12, 1)))
+
+	assert.True(t, arrow.IntersectsCircle(pixel.C(pixel.V(5, 2), 1)))
+	assert.True(t, arrow.IntersectsCircle(pixel.C(pixel.V(5, 50), 100)))
+	assert.True(t, arrow.IntersectsCircle(pixel.C(pixel.V(12, 5), 2)))
+	assert.False(t, arrow.IntersectsCircle(pixel.C(pixel.V(5, 9), 1)))
+	assert.False(t, arrow.IntersectsCircle(pixel.C(pixel.V(12, 5), 1.9)))
+}
@@ -0,0 +1,465 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// PolygonSet is an area bounded by any number of Polygons, such as a Polygon with holes or several
+// separate Polygons. A point belongs to the area if it's inside an odd number of the Polygons (the
+// even-odd rule), so the winding of the Polygons doesn't matter and a Polygon placed inside
+// another one makes a hole in it.
+//
+// PolygonSets can be combined with boolean operations and offset:
+//
+//   terrain := pixel.PolygonSet{ground}
+//   terrain = terrain.Difference(pixel.PolygonSet{crater.Moved(impact)})
+//   outline := terrain.Offset(2).Difference(terrain)
+//
+// The PolygonSets returned by these operations are normalized: their Polygons don't cross each
+// other, the outer boundaries are wound counter-clockwise and the boundaries of holes clockwise.
+type PolygonSet []Polygon
+
+// String returns the string representation of the PolygonSet.
+//
+//   ps := pixel.PolygonSet{pixel.Polygon{pixel.V(0, 0), pixel.V(1, 0), pixel.V(0, 1)}}
+//   ps.String()     // returns "PolygonSet(Polygon(Vec(0, 0), Vec(1, 0), Vec(0, 1)))"
+//   fmt.Println(ps) // PolygonSet(Polygon(Vec(0, 0), Vec(1, 0), Vec(0, 1)))
+func (ps PolygonSet) String() string {
+	polygons := make([]string, len(ps))
+	for i, p := range ps {
+		polygons[i] = p.String()
+	}
+	return fmt.Sprintf("PolygonSet(%s)", strings.Join(polygons, ", "))
+}
+
+// Bounds returns the smallest Rect which contains the whole PolygonSet. The Rect is normalized.
+func (ps PolygonSet) Bounds() Rect {
+	r, empty := ZR, true
+	for _, p := range ps {
+		if len(p) == 0 {
+			continue
+		}
+		if empty {
+			r, empty = p.Bounds(), false
+			continue
+		}
+		r = r.Union(p.Bounds())
+	}
+	return r
+}
+
+// Area returns the area of the PolygonSet. The PolygonSet must be normalized.
+func (ps PolygonSet) Area() float64 {
+	var area float64
+	for _, p := range ps {
+		area += p.SignedArea()
+	}
+	return area
+}
+
+// Contains checks whether a vector u is contained within the PolygonSet (including its border).
+func (ps PolygonSet) Contains(u Vec) bool {
+	for _, p := range ps {
+		if p.onBorder(u) {
+			return true
+		}
+	}
+	return ps.crossedOddly(u)
+}
+
+func (ps PolygonSet) crossedOddly(u Vec) bool {
+	inside := false
+	for _, p := range ps {
+		if p.crossedOddly(u) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (ps PolygonSet) edges() []Line {
+	var edges []Line
+	for _, p := range ps {
+		for i := range p {
+			edges = append(edges, Line{p[i], p[(i+1)%len(p)]})
+		}
+	}
+	return edges
+}
+
+// Union returns the area covered by either of the PolygonSets.
+func (ps PolygonSet) Union(other PolygonSet) PolygonSet {
+	return overlay(append(ps.edges(), other.edges()...), func(u Vec) bool {
+		return ps.crossedOddly(u) || other.crossedOddly(u)
+	})
+}
+
+// Intersect returns the area covered by both of the PolygonSets.
+func (ps PolygonSet) Intersect(other PolygonSet) PolygonSet {
+	return overlay(append(ps.edges(), other.edges()...), func(u Vec) bool {
+		return ps.crossedOddly(u) && other.crossedOddly(u)
+	})
+}
+
+// Difference returns the area covered by this PolygonSet, but not by the other one.
+func (ps PolygonSet) Difference(other PolygonSet) PolygonSet {
+	return overlay(append(ps.edges(), other.edges()...), func(u Vec) bool {
+		return ps.crossedOddly(u) && !other.crossedOddly(u)
+	})
+}
+
+// Xor returns the area covered by exactly one of the PolygonSets.
+func (ps PolygonSet) Xor(other PolygonSet) PolygonSet {
+	return overlay(append(ps.edges(), other.edges()...), func(u Vec) bool {
+		return ps.crossedOddly(u) != other.crossedOddly(u)
+	})
+}
+
+// offsetSegments is the number of segments approximating a full circle in Offset.
+const offsetSegments = 32
+
+// Offset returns the PolygonSet inflated by delta, or deflated if delta is negative. All edges
+// move by |delta| outwards (or inwards) and the convex corners are rounded. Rounded corners are
+// approximated by straight segments, 32 per full circle.
+//
+// Deflating removes the parts of the area thinner than twice |delta| completely.
+func (ps PolygonSet) Offset(delta float64) PolygonSet {
+	if delta == 0 {
+		return ps.Union(nil)
+	}
+
+	// the offset area is the area with all points closer than |delta| to its border added (or
+	// removed), which is a union of capsules around the edges
+	radius := math.Abs(delta)
+	var (
+		edges    = ps.edges()
+		capsules PolygonSet
+		bounds   []Rect
+	)
+	for _, e := range edges {
+		c := capsule(e.A, e.B, radius)
+		capsules = append(capsules, c)
+		bounds = append(bounds, c.Bounds())
+	}
+	inCapsule := func(u Vec) bool {
+		for i, c := range capsules {
+			if bounds[i].Contains(u) && c.crossedOddly(u) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return overlay(append(edges, capsules.edges()...), func(u Vec) bool {
+		if delta > 0 {
+			return ps.crossedOddly(u) || inCapsule(u)
+		}
+		return ps.crossedOddly(u) && !inCapsule(u)
+	})
+}
+
+// capsule returns the convex Polygon approximating all points within the radius from the segment
+// between a and b.
+func capsule(a, b Vec, radius float64) Polygon {
+	const half = offsetSegments / 2
+	angle := a.To(b).Angle()
+	p := make(Polygon, 0, 2*(half+1))
+	for i := 0; i <= half; i++ {
+		p = append(p, b.Add(Unit(angle-math.Pi/2+math.Pi*float64(i)/half).Scaled(radius)))
+	}
+	for i := 0; i <= half; i++ {
+		p = append(p, a.Add(Unit(angle+math.Pi/2+math.Pi*float64(i)/half).Scaled(radius)))
+	}
+	return p
+}
+
+// TrianglesData returns the triangulation of the PolygonSet as TrianglesData ready to be drawn.
+// All vertices are white and have no Picture. The PolygonSet must be normalized.
+func (ps PolygonSet) TrianglesData() *TrianglesData {
+	var (
+		outers []Polygon
+		holes  [][]Polygon
+	)
+	for _, p := range ps {
+		if len(p) >= 3 && p.SignedArea() > 0 {
+			outers = append(outers, p)
+			holes = append(holes, nil)
+		}
+	}
+	for _, p := range ps {
+		if len(p) < 3 || p.SignedArea() >= 0 {
+			continue
+		}
+		// the filled area is left of the clockwise edges of the hole and belongs to the smallest
+		// outer boundary around it
+		edge := p[0].To(p[1])
+		probe := p[0].Add(edge.Scaled(0.5)).Add(edge.Normal().Scaled(1e-6))
+		parent := -1
+		for i, outer := range outers {
+			if outer.Contains(probe) && (parent == -1 || outer.Area() < outers[parent].Area()) {
+				parent = i
+			}
+		}
+		if parent != -1 {
+			holes[parent] = append(holes[parent], p)
+		}
+	}
+
+	tri := MakeTrianglesData(0)
+	for i, outer := range outers {
+		*tri = append(*tri, *outer.TrianglesData(holes[i]...)...)
+	}
+	return tri
+}
+
+// overlay finds the boundary of the area given by the inside function, knowing that all of the
+// boundary lies on the edges. It splits the edges at all of their intersections, keeps the pieces
+// which have the area on exactly one side and links them into Polygons.
+//
+// The inside function is only ever called on points which don't lie on any of the edges.
+func overlay(edges []Line, inside func(Vec) bool) PolygonSet {
+	var bounds Rect
+	for i, e := range edges {
+		r := Rect{e.A, e.B}.Norm()
+		if i == 0 {
+			bounds = r
+		} else {
+			bounds = bounds.Union(r)
+		}
+	}
+	scale := math.Max(
+		math.Max(math.Abs(bounds.Min.X), math.Abs(bounds.Max.X)),
+		math.Max(math.Abs(bounds.Min.Y), math.Abs(bounds.Max.Y)),
+	)
+	if scale == 0 {
+		return nil
+	}
+	// points closer than snapDist are considered the same point and the area is sampled at
+	// sampleDist from the edges
+	snapDist, sampleDist := scale*1e-10, scale*1e-7
+
+	snap := newPointSnapper(snapDist)
+	type segment struct{ a, b int }
+	var (
+		segments []segment
+		seen     = make(map[segment]bool)
+	)
+	for i, splits := range splitEdges(edges, snapDist) {
+		prev := snap.id(edges[i].A)
+		for _, p := range append(splits, edges[i].B) {
+			next := snap.id(p)
+			s := segment{prev, next}
+			if s.a > s.b {
+				s.a, s.b = s.b, s.a
+			}
+			if prev != next && !seen[s] {
+				seen[s] = true
+				segments = append(segments, segment{prev, next})
+			}
+			prev = next
+		}
+	}
+
+	// keep the segments separating the area from the rest, oriented to have the area on the left
+	var (
+		links    []segment
+		outgoing = make(map[int][]int)
+	)
+	for _, s := range segments {
+		a, b := snap.points[s.a], snap.points[s.b]
+		mid := Lerp(a, b, 0.5)
+		normal := a.To(b).Normal().Unit().Scaled(sampleDist)
+		left, right := inside(mid.Add(normal)), inside(mid.Sub(normal))
+		if left == right {
+			continue
+		}
+		if right {
+			s.a, s.b = s.b, s.a
+		}
+		outgoing[s.a] = append(outgoing[s.a], len(links))
+		links = append(links, s)
+	}
+
+	// link the segments into Polygons, taking the sharpest right turn at each vertex shared by
+	// more boundaries, so that they stay separate
+	var (
+		result PolygonSet
+		used   = make([]bool, len(links))
+	)
+	for first := range links {
+		if used[first] {
+			continue
+		}
+		var (
+			p      Polygon
+			cur    = first
+			closed bool
+		)
+		for {
+			used[cur] = true
+			l := links[cur]
+			p = append(p, snap.points[l.a])
+			if l.b == links[first].a {
+				closed = true
+				break
+			}
+			dir := snap.points[l.a].To(snap.points[l.b])
+			next, bestTurn := -1, math.Inf(+1)
+			for _, candidate := range outgoing[l.b] {
+				if used[candidate] {
+					continue
+				}
+				c := links[candidate]
+				out := snap.points[c.a].To(snap.points[c.b])
+				if turn := math.Atan2(dir.Cross(out), dir.Dot(out)); turn < bestTurn {
+					next, bestTurn = candidate, turn
+				}
+			}
+			if next == -1 {
+				break
+			}
+			cur = next
+		}
+		if !closed {
+			continue
+		}
+		if p = removeCollinear(p, snapDist); len(p) >= 3 {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// splitEdges returns the points at which each of the edges intersects the other edges, sorted
+// from the start of the edge to its end. Endpoints of the edges aren't included.
+func splitEdges(edges []Line, tolerance float64) [][]Vec {
+	type split struct {
+		t float64
+		p Vec
+	}
+	splits := make([][]split, len(edges))
+	add := func(i int, t float64, p Vec) {
+		if t > 0 && t < 1 && p != edges[i].A && p != edges[i].B {
+			splits[i] = append(splits[i], split{t, p})
+		}
+	}
+	param := func(l Line, p Vec) float64 {
+		ab := l.A.To(l.B)
+		return l.A.To(p).Dot(ab) / ab.SqLen()
+	}
+
+	bounds := make([]Rect, len(edges))
+	for i, e := range edges {
+		bounds[i] = Rect{e.A, e.B}.Norm()
+		bounds[i].Min = bounds[i].Min.Sub(V(tolerance, tolerance))
+		bounds[i].Max = bounds[i].Max.Add(V(tolerance, tolerance))
+	}
+
+	for i := range edges {
+		for j := i + 1; j < len(edges); j++ {
+			if !bounds[i].Intersects(bounds[j]) {
+				continue
+			}
+			a, b, c, d := edges[i].A, edges[i].B, edges[j].A, edges[j].B
+			ab, cd, ac := a.To(b), c.To(d), a.To(c)
+			if ab == ZV || cd == ZV {
+				continue
+			}
+			denom := ab.Cross(cd)
+
+			if math.Abs(denom) <= 1e-12*ab.Len()*cd.Len() {
+				// parallel edges only split each other if they overlap
+				if math.Abs(ac.Cross(ab)) > tolerance*ab.Len() {
+					continue
+				}
+				add(i, param(edges[i], c), c)
+				add(i, param(edges[i], d), d)
+				add(j, param(edges[j], a), a)
+				add(j, param(edges[j], b), b)
+				continue
+			}
+
+			// allow for a little imprecision, so that edges ending on other edges split them
+			t, s := ac.Cross(cd)/denom, ac.Cross(ab)/denom
+			tt, st := tolerance/ab.Len(), tolerance/cd.Len()
+			if t < -tt || t > 1+tt || s < -st || s > 1+st {
+				continue
+			}
+			var p Vec
+			switch {
+			case s <= st:
+				p = c
+			case s >= 1-st:
+				p = d
+			case t <= tt:
+				p = a
+			case t >= 1-tt:
+				p = b
+			default:
+				p = Lerp(a, b, t)
+			}
+			add(i, param(edges[i], p), p)
+			add(j, param(edges[j], p), p)
+		}
+	}
+
+	points := make([][]Vec, len(edges))
+	for i := range splits {
+		sort.Slice(splits[i], func(a, b int) bool { return splits[i][a].t < splits[i][b].t })
+		for _, s := range splits[i] {
+			points[i] = append(points[i], s.p)
+		}
+	}
+	return points
+}
+
+// removeCollinear removes the vertices of the Polygon lying on the line between their neighbours.
+func removeCollinear(p Polygon, tolerance float64) Polygon {
+	for changed := true; changed && len(p) >= 3; {
+		changed = false
+		for i := 0; i < len(p) && len(p) >= 3; i++ {
+			prev, next := p[(i-1+len(p))%len(p)], p[(i+1)%len(p)]
+			if segmentClosest(prev, next, p[i]).To(p[i]).Len() <= tolerance {
+				p = append(p[:i], p[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return p
+}
+
+// pointSnapper merges points closer than a distance into one, so that the same point computed in
+// different ways doesn't fall apart.
+type pointSnapper struct {
+	dist   float64
+	grid   map[[2]int64][]int
+	points []Vec
+}
+
+func newPointSnapper(dist float64) *pointSnapper {
+	return &pointSnapper{dist: dist, grid: make(map[[2]int64][]int)}
+}
+
+// id returns the index of the point in the points slice, adding it if there is no point close to
+// it yet.
+func (ps *pointSnapper) id(v Vec) int {
+	cx, cy := int64(math.Floor(v.X/ps.dist)), int64(math.Floor(v.Y/ps.dist))
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, id := range ps.grid[[2]int64{cx + dx, cy + dy}] {
+				if ps.points[id].To(v).Len() <= ps.dist {
+					return id
+				}
+			}
+		}
+	}
+	id := len(ps.points)
+	ps.points = append(ps.points, v)
+	key := [2]int64{cx, cy}
+	ps.grid[key] = append(ps.grid[key], id)
+	return id
+}
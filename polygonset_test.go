@@ -0,0 +1,149 @@
+package pixel_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertNormalized checks that the Polygons of the PolygonSet are simple and wound so that the
+// area is on their left.
+func assertNormalized(t *testing.T, ps pixel.PolygonSet) {
+	t.Helper()
+	for _, p := range ps {
+		if !assert.GreaterOrEqual(t, len(p), 3) {
+			continue
+		}
+		for i := range p {
+			a, b := p[i], p[(i+1)%len(p)]
+			mid := pixel.Lerp(a, b, 0.5)
+			normal := a.To(b).Normal().Unit().Scaled(1e-3)
+			assert.True(t, ps.Contains(mid.Add(normal)), "area isn't left of the edge %v %v", a, b)
+			assert.False(t, ps.Contains(mid.Sub(normal)), "area is right of the edge %v %v", a, b)
+		}
+	}
+}
+
+func TestPolygonSet_Boolean(t *testing.T) {
+	a := pixel.PolygonSet{square}
+	b := pixel.PolygonSet{square.Moved(pixel.V(5, 5))}
+
+	tests := []struct {
+		name     string
+		result   pixel.PolygonSet
+		area     float64
+		polygons int
+		in, out  []pixel.Vec
+	}{
+		{"union", a.Union(b), 175, 1, []pixel.Vec{pixel.V(1, 1), pixel.V(14, 14)}, []pixel.Vec{pixel.V(1, 14)}},
+		{"intersect", a.Intersect(b), 25, 1, []pixel.Vec{pixel.V(6, 6)}, []pixel.Vec{pixel.V(1, 1), pixel.V(14, 14)}},
+		{"difference", a.Difference(b), 75, 1, []pixel.Vec{pixel.V(1, 1)}, []pixel.Vec{pixel.V(6, 6), pixel.V(14, 14)}},
+		{"xor", a.Xor(b), 150, 2, []pixel.Vec{pixel.V(1, 1), pixel.V(14, 14)}, []pixel.Vec{pixel.V(6, 6)}},
+		{"hole", a.Difference(pixel.PolygonSet{square.Scaled(pixel.V(5, 5), 0.4)}), 84, 2, []pixel.Vec{pixel.V(1, 1)}, []pixel.Vec{pixel.V(5, 5)}},
+		{"touching", a.Union(pixel.PolygonSet{square.Moved(pixel.V(10, 0))}), 200, 1, []pixel.Vec{pixel.V(10, 5)}, nil},
+		{"same union", a.Union(pixel.PolygonSet{square.Reversed()}), 100, 1, []pixel.Vec{pixel.V(5, 5)}, nil},
+		{"same xor", a.Xor(a), 0, 0, nil, []pixel.Vec{pixel.V(5, 5)}},
+		{"apart", a.Intersect(pixel.PolygonSet{square.Moved(pixel.V(20, 0))}), 0, 0, nil, []pixel.Vec{pixel.V(5, 5)}},
+		{"concave", pixel.PolygonSet{arrow}.Intersect(pixel.PolygonSet{pixel.Polygon{pixel.V(0, 5), pixel.V(10, 5), pixel.V(10, 9), pixel.V(0, 9)}}), 40 - 160.0/7, 2, []pixel.Vec{pixel.V(0.5, 8)}, []pixel.Vec{pixel.V(5, 8)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertNormalized(t, tt.result)
+			assert.Len(t, tt.result, tt.polygons)
+			assert.InDelta(t, tt.area, tt.result.Area(), 1e-9)
+			for _, u := range tt.in {
+				assert.True(t, tt.result.Contains(u), "%v should be inside", u)
+			}
+			for _, u := range tt.out {
+				assert.False(t, tt.result.Contains(u), "%v should be outside", u)
+			}
+		})
+	}
+
+	// collinear vertices at the seams are removed
+	assert.Len(t, a.Union(pixel.PolygonSet{square.Moved(pixel.V(10, 0))})[0], 4)
+}
+
+func TestPolygonSet_Boolean_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	star := func() pixel.PolygonSet {
+		var p pixel.Polygon
+		center := pixel.V(rnd.Float64()*20, rnd.Float64()*20)
+		n := 3 + rnd.Intn(12)
+		for j := 0; j < n; j++ {
+			angle := (float64(j) + rnd.Float64()*0.9) / float64(n) * 2 * math.Pi
+			p = append(p, center.Add(pixel.Unit(angle).Scaled(1+rnd.Float64()*20)))
+		}
+		return pixel.PolygonSet{p}
+	}
+
+	for i := 0; i < 50; i++ {
+		a, b := star(), star()
+		union, intersect := a.Union(b), a.Intersect(b)
+		difference, xor := a.Difference(b), a.Xor(b)
+		for _, ps := range []pixel.PolygonSet{union, intersect, difference, xor} {
+			assertNormalized(t, ps)
+		}
+
+		areaA, areaB := a[0].Area(), b[0].Area()
+		assert.InDelta(t, areaA+areaB, union.Area()+intersect.Area(), 1e-6)
+		assert.InDelta(t, areaA, difference.Area()+intersect.Area(), 1e-6)
+		assert.InDelta(t, union.Area()-intersect.Area(), xor.Area(), 1e-6)
+
+		for j := 0; j < 100; j++ {
+			u := pixel.V(rnd.Float64()*60-20, rnd.Float64()*60-20)
+			inA, inB := a.Contains(u), b.Contains(u)
+			assert.Equal(t, inA || inB, union.Contains(u))
+			assert.Equal(t, inA && inB, intersect.Contains(u))
+			assert.Equal(t, inA && !inB, difference.Contains(u))
+			assert.Equal(t, inA != inB, xor.Contains(u))
+		}
+	}
+}
+
+func TestPolygonSet_Offset(t *testing.T) {
+	a := pixel.PolygonSet{square}
+
+	// a circle approximated by 32 segments
+	circle := 16 * math.Sin(2*math.Pi/32)
+
+	inflated := a.Offset(1)
+	assertNormalized(t, inflated)
+	assert.InDelta(t, 100+40+circle, inflated.Area(), 1e-9)
+	assert.True(t, inflated.Contains(pixel.V(-0.9, 5)))
+	assert.False(t, inflated.Contains(pixel.V(-1.1, 5)))
+
+	deflated := a.Offset(-1)
+	assertNormalized(t, deflated)
+	assert.InDelta(t, 64, deflated.Area(), 1e-9)
+	assert.Empty(t, a.Offset(-6))
+
+	// inflating fills the notch of the arrow, deflating splits it in two
+	notch := pixel.V(5, 4)
+	assert.True(t, pixel.PolygonSet{arrow}.Offset(1).Contains(notch))
+	assertNormalized(t, pixel.PolygonSet{arrow}.Offset(1))
+	assert.Len(t, pixel.PolygonSet{arrow}.Difference(pixel.PolygonSet{pixel.Polygon{pixel.V(4, 0), pixel.V(6, 0), pixel.V(6, 4), pixel.V(4, 4)}}).Offset(-0.5), 2)
+
+	// a ring made by inflating a hole
+	ring := pixel.PolygonSet{square, square.Scaled(pixel.V(5, 5), 0.4)}.Offset(-0.5)
+	assertNormalized(t, ring)
+	assert.Len(t, ring, 2)
+	assert.False(t, ring.Contains(pixel.V(5, 5)))
+	assert.False(t, ring.Contains(pixel.V(3.2, 5)))
+}
+
+func TestPolygonSet_TrianglesData(t *testing.T) {
+	ps := pixel.PolygonSet{square, square.Scaled(pixel.V(5, 5), 0.4)}.Union(pixel.PolygonSet{square.Moved(pixel.V(20, 0))})
+	tri := ps.TrianglesData()
+	var area float64
+	for i := 0; i < tri.Len(); i += 3 {
+		a, b, c := (*tri)[i].Position, (*tri)[i+1].Position, (*tri)[i+2].Position
+		area += a.To(b).Cross(a.To(c)) / 2
+		assert.True(t, ps.Contains(a.Add(b).Add(c).Scaled(1.0/3)))
+	}
+	assert.InDelta(t, 184, area, 1e-9)
+	assert.InDelta(t, 184, ps.Area(), 1e-9)
+}
@@ -0,0 +1,354 @@
+package pixel
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Raster is a ComposeTarget which rasterizes Triangles in software directly into a PictureData.
+// It needs no OpenGL context, so it's suitable for tests, servers and other headless environments.
+//
+// It supports TrianglesPosition, TrianglesColor, TrianglesPicture, TrianglesClipped,
+// TrianglesIndexed and PictureColor. The result of drawing onto a Raster follows the result of drawing onto a
+// pixelgl.Canvas as closely as possible.
+//
+// The clipping rectangles of TrianglesClipped are specified in the coordinates of the Raster's
+// PictureData.
+type Raster struct {
+	pd *PictureData
+
+	cmp    ComposeMethod
+	mat    Matrix
+	col    RGBA
+	smooth bool
+}
+
+var _ ComposeTarget = (*Raster)(nil)
+
+// NewRaster creates a new Raster drawing into the supplied PictureData.
+//
+// The PictureData is drawn into directly, so all drawing is immediately visible in it.
+func NewRaster(pd *PictureData) *Raster {
+	r := &Raster{pd: pd}
+	r.SetMatrix(IM)
+	r.SetColorMask(Alpha(1))
+	return r
+}
+
+// MakeTriangles creates a specialized copy of the supplied Triangles that draws onto this Raster.
+//
+// TrianglesPosition, TrianglesColor, TrianglesPicture, TrianglesClipped and TrianglesIndexed are
+// supported.
+func (r *Raster) MakeTriangles(t Triangles) TargetTriangles {
+	rt := &rasterTriangles{
+		TrianglesData: MakeTrianglesData(t.Len()),
+		dst:           r,
+	}
+	rt.Update(t)
+	return rt
+}
+
+// MakePicture creates a specialized copy of the supplied Picture that draws onto this Raster.
+//
+// PictureColor is supported.
+func (r *Raster) MakePicture(p Picture) TargetPicture {
+	if rp, ok := p.(*rasterPicture); ok {
+		return &rasterPicture{
+			pd:  rp.pd,
+			dst: r,
+		}
+	}
+	return &rasterPicture{
+		pd:  PictureDataFromPicture(p),
+		dst: r,
+	}
+}
+
+// SetMatrix sets a Matrix that every point will be projected by.
+func (r *Raster) SetMatrix(m Matrix) {
+	r.mat = m
+}
+
+// SetColorMask sets a color that every color in triangles or a picture will be multiplied by.
+func (r *Raster) SetColorMask(c color.Color) {
+	if c == nil {
+		r.col = Alpha(1)
+		return
+	}
+	r.col = ToRGBA(c)
+}
+
+// SetComposeMethod sets a Porter-Duff composition method or a blend mode to be used in the
+// following draws onto this Raster.
+func (r *Raster) SetComposeMethod(cmp ComposeMethod) {
+	r.cmp = cmp
+}
+
+// SetSmooth sets whether stretched Pictures drawn onto this Raster should be drawn smooth or
+// pixely.
+func (r *Raster) SetSmooth(smooth bool) {
+	r.smooth = smooth
+}
+
+// Smooth returns whether stretched Pictures drawn onto this Raster are set to be drawn smooth or
+// pixely.
+func (r *Raster) Smooth() bool {
+	return r.smooth
+}
+
+// Clear fills the whole Raster with a single color.
+func (r *Raster) Clear(c color.Color) {
+	rgba := toColorRGBA(ToRGBA(c).Mul(r.col))
+	for i := range r.pd.Pix {
+		r.pd.Pix[i] = rgba
+	}
+}
+
+// Bounds returns the rectangular bounds of the Raster.
+func (r *Raster) Bounds() Rect {
+	return r.pd.Bounds()
+}
+
+// Color returns the color of the pixel over the given position inside the Raster.
+func (r *Raster) Color(at Vec) RGBA {
+	return r.pd.Color(at)
+}
+
+// PictureData returns the PictureData this Raster draws into.
+func (r *Raster) PictureData() *PictureData {
+	return r.pd
+}
+
+// rasterVertex is a vertex with its position already projected by the Raster's Matrix.
+type rasterVertex struct {
+	pos Vec
+	col RGBA
+	pic Vec
+	in  float64
+}
+
+func (r *Raster) drawTriangles(rt *rasterTriangles, pic *PictureData) {
+	tri := rt.TrianglesData
+	n := len(*tri)
+	if rt.indexed {
+		n = len(rt.indices)
+	}
+	for i := 0; i+2 < n; i += 3 {
+		var (
+			vs [3]rasterVertex
+			js = [3]int{i, i + 1, i + 2}
+		)
+		for k := range vs {
+			if rt.indexed {
+				js[k] = rt.indices[i+k]
+				if js[k] < 0 || js[k] >= len(*tri) {
+					panic(fmt.Errorf("(%T).Draw: index %d out of range of %d vertices", rt, js[k], len(*tri)))
+				}
+			}
+			v := (*tri)[js[k]]
+			vs[k] = rasterVertex{
+				pos: r.mat.Project(v.Position),
+				col: v.Color,
+				pic: v.Picture,
+				in:  v.Intensity,
+			}
+		}
+		clip, isClipped := (*tri)[js[0]].ClipRect, (*tri)[js[0]].IsClipped
+		r.fillTriangle(vs, pic, clip, isClipped)
+	}
+}
+
+// isTopLeft reports whether the edge from a to b of a counter-clockwise triangle is a top or a
+// left edge. Pixel centers lying exactly on such edges are filled, which makes sure that pixels
+// on an edge shared by two triangles are drawn exactly once.
+func isTopLeft(a, b Vec) bool {
+	d := a.To(b)
+	return d.Y < 0 || (d.Y == 0 && d.X < 0)
+}
+
+func (r *Raster) fillTriangle(vs [3]rasterVertex, pic *PictureData, clip Rect, isClipped bool) {
+	area := vs[0].pos.To(vs[1].pos).Cross(vs[0].pos.To(vs[2].pos))
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		vs[1], vs[2] = vs[2], vs[1]
+		area = -area
+	}
+
+	bounds := R(
+		math.Min(vs[0].pos.X, math.Min(vs[1].pos.X, vs[2].pos.X)),
+		math.Min(vs[0].pos.Y, math.Min(vs[1].pos.Y, vs[2].pos.Y)),
+		math.Max(vs[0].pos.X, math.Max(vs[1].pos.X, vs[2].pos.X)),
+		math.Max(vs[0].pos.Y, math.Max(vs[1].pos.Y, vs[2].pos.Y)),
+	)
+	if isClipped {
+		bounds = bounds.Intersect(clip.Norm())
+	}
+	bounds = bounds.Intersect(r.pd.Rect)
+	if bounds == ZR {
+		return
+	}
+
+	var topLeft [3]bool
+	for k := range vs {
+		topLeft[k] = isTopLeft(vs[(k+1)%3].pos, vs[(k+2)%3].pos)
+	}
+
+	minX, minY := math.Floor(bounds.Min.X), math.Floor(bounds.Min.Y)
+	for y := minY; y < bounds.Max.Y; y++ {
+		for x := minX; x < bounds.Max.X; x++ {
+			p := V(x+0.5, y+0.5)
+			if !bounds.Contains(p) {
+				continue
+			}
+
+			// barycentric weights, w[k] is the weight of the vertex opposite to the k-th edge
+			var w [3]float64
+			inside := true
+			for k := range vs {
+				a, b := vs[(k+1)%3].pos, vs[(k+2)%3].pos
+				w[k] = a.To(b).Cross(a.To(p))
+				if w[k] < 0 || (w[k] == 0 && !topLeft[k]) {
+					inside = false
+					break
+				}
+			}
+			if !inside {
+				continue
+			}
+
+			var (
+				col RGBA
+				pos Vec
+				in  float64
+			)
+			for k := range vs {
+				t := w[k] / area
+				col = col.Add(vs[k].col.Scaled(t))
+				pos = pos.Add(vs[k].pic.Scaled(t))
+				in += vs[k].in * t
+			}
+
+			if pic != nil && in != 0 {
+				tex := r.sample(pic, pos)
+				col = col.Scaled(1 - in).Add(col.Mul(tex).Scaled(in))
+			}
+			col = col.Mul(r.col)
+
+			idx := r.pd.Index(p)
+			dst := ToRGBA(r.pd.Pix[idx])
+			r.pd.Pix[idx] = toColorRGBA(r.cmp.Compose(col, dst))
+		}
+	}
+}
+
+// sample returns the color of the Picture at the given Picture coordinates. Coordinates outside
+// of the Picture are clamped to its edges.
+func (r *Raster) sample(pic *PictureData, at Vec) RGBA {
+	if !r.smooth {
+		return pic.Color(clampToPixels(pic.Rect, at))
+	}
+
+	// bilinear filtering between the four closest pixel centers
+	at = at.Sub(V(0.5, 0.5))
+	base := at.Floor()
+	fx, fy := at.X-base.X, at.Y-base.Y
+	c00 := pic.Color(clampToPixels(pic.Rect, base))
+	c10 := pic.Color(clampToPixels(pic.Rect, base.Add(V(1, 0))))
+	c01 := pic.Color(clampToPixels(pic.Rect, base.Add(V(0, 1))))
+	c11 := pic.Color(clampToPixels(pic.Rect, base.Add(V(1, 1))))
+	bottom := c00.Scaled(1 - fx).Add(c10.Scaled(fx))
+	top := c01.Scaled(1 - fx).Add(c11.Scaled(fx))
+	return bottom.Scaled(1 - fy).Add(top.Scaled(fy))
+}
+
+// clampToPixels returns a position inside the pixel of the Rect closest to the given position.
+func clampToPixels(rect Rect, at Vec) Vec {
+	// the Max together with the Floor is a trick to get all of the pixels, see
+	// PictureDataFromPicture
+	return V(
+		math.Max(Clamp(math.Floor(at.X), math.Floor(rect.Min.X), math.Ceil(rect.Max.X)-1), rect.Min.X),
+		math.Max(Clamp(math.Floor(at.Y), math.Floor(rect.Min.Y), math.Ceil(rect.Max.Y)-1), rect.Min.Y),
+	)
+}
+
+func toColorRGBA(c RGBA) color.RGBA {
+	return color.RGBA{
+		R: uint8(Clamp(c.R, 0, 1)*255 + 0.5),
+		G: uint8(Clamp(c.G, 0, 1)*255 + 0.5),
+		B: uint8(Clamp(c.B, 0, 1)*255 + 0.5),
+		A: uint8(Clamp(c.A, 0, 1)*255 + 0.5),
+	}
+}
+
+type rasterTriangles struct {
+	*TrianglesData
+	indices []int
+	indexed bool
+	dst     *Raster
+}
+
+func (rt *rasterTriangles) SetLen(len int) {
+	if rt.indexed && len < rt.Len() {
+		rt.indices = sliceIndices(rt.indices, 0, len)
+	}
+	rt.TrianglesData.SetLen(len)
+}
+
+func (rt *rasterTriangles) Slice(i, j int) Triangles {
+	st := &rasterTriangles{
+		TrianglesData: rt.TrianglesData.Slice(i, j).(*TrianglesData),
+		indexed:       rt.indexed,
+		dst:           rt.dst,
+	}
+	if rt.indexed {
+		st.indices = sliceIndices(rt.indices, i, j)
+	}
+	return st
+}
+
+func (rt *rasterTriangles) Update(t Triangles) {
+	rt.TrianglesData.Update(t)
+	indices, indexed := indicesOf(t)
+	rt.indices, rt.indexed = append(rt.indices[:0], indices...), indexed
+}
+
+func (rt *rasterTriangles) Copy() Triangles {
+	return &rasterTriangles{
+		TrianglesData: rt.TrianglesData.Copy().(*TrianglesData),
+		indices:       append([]int(nil), rt.indices...),
+		indexed:       rt.indexed,
+		dst:           rt.dst,
+	}
+}
+
+func (rt *rasterTriangles) Indices() (indices []int, is bool) {
+	return rt.indices, rt.indexed
+}
+
+func (rt *rasterTriangles) Draw() {
+	rt.dst.drawTriangles(rt, nil)
+}
+
+type rasterPicture struct {
+	pd  *PictureData
+	dst *Raster
+}
+
+func (rp *rasterPicture) Bounds() Rect {
+	return rp.pd.Bounds()
+}
+
+func (rp *rasterPicture) Color(at Vec) RGBA {
+	return rp.pd.Color(at)
+}
+
+func (rp *rasterPicture) Draw(t TargetTriangles) {
+	rt := t.(*rasterTriangles)
+	if rp.dst != rt.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Raster", rp))
+	}
+	rp.dst.drawTriangles(rt, rp.pd)
+}
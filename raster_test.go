@@ -0,0 +1,256 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixeltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkRasterSprite(b *testing.B) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 64, 64))
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+	r := pixel.NewRaster(pixel.MakePictureData(pixel.R(0, 0, 256, 256)))
+	for i := 0; i < b.N; i++ {
+		sprite.Draw(r, pixel.IM.Moved(pixel.V(128, 128)))
+	}
+}
+
+// quad returns TrianglesData of a rectangle split into two triangles along its diagonal.
+func quad(rect pixel.Rect, col pixel.RGBA) *pixel.TrianglesData {
+	tri := pixel.MakeTrianglesData(6)
+	for i, v := range []pixel.Vec{
+		rect.Min, pixel.V(rect.Max.X, rect.Min.Y), rect.Max,
+		rect.Min, rect.Max, pixel.V(rect.Min.X, rect.Max.Y),
+	} {
+		(*tri)[i].Position = v
+		(*tri)[i].Color = col
+	}
+	return tri
+}
+
+func TestRaster_Triangles(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	r := pixel.NewRaster(pd)
+
+	r.MakeTriangles(quad(pixel.R(1, 1, 3, 3), pixel.RGB(1, 0, 0))).Draw()
+
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			want := color.RGBA{}
+			if 1 <= x && x < 3 && 1 <= y && y < 3 {
+				want = color.RGBA{R: 255, A: 255}
+			}
+			assert.Equal(t, want, pd.Pix[y*pd.Stride+x], "pixel (%d, %d)", x, y)
+		}
+	}
+}
+
+func TestRaster_SharedEdges(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 8, 8))
+	r := pixel.NewRaster(pd)
+	r.SetComposeMethod(pixel.ComposePlus)
+
+	// the diagonal of the quad passes exactly through pixel centers, so every pixel must be
+	// filled by exactly one of the two triangles
+	r.MakeTriangles(quad(pixel.R(0, 0, 8, 8), pixel.Alpha(0.25))).Draw()
+
+	for i, pix := range pd.Pix {
+		assert.Equal(t, uint8(64), pix.A, "pixel %d", i)
+	}
+}
+
+func TestRaster_MatrixAndColorMask(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	r := pixel.NewRaster(pd)
+
+	r.SetMatrix(pixel.IM.Moved(pixel.V(2, 2)))
+	r.SetColorMask(pixel.RGB(0, 1, 0))
+	r.MakeTriangles(quad(pixel.R(0, 0, 2, 2), pixel.RGB(1, 1, 1))).Draw()
+
+	assert.Equal(t, color.RGBA{}, pd.Pix[pd.Index(pixel.V(1, 1))])
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, pd.Pix[pd.Index(pixel.V(3, 3))])
+}
+
+func TestRaster_ComposeMethod(t *testing.T) {
+	for _, cmp := range append([]pixel.ComposeMethod{
+		pixel.ComposeOver,
+		pixel.ComposeIn,
+		pixel.ComposeOut,
+		pixel.ComposeAtop,
+		pixel.ComposeRover,
+		pixel.ComposeRin,
+		pixel.ComposeRout,
+		pixel.ComposeRatop,
+		pixel.ComposeXor,
+		pixel.ComposePlus,
+		pixel.ComposeCopy,
+	}, blendModes...) {
+		pd := pixel.MakePictureData(pixel.R(0, 0, 1, 1))
+		r := pixel.NewRaster(pd)
+		dst := pixel.RGB(0, 0, 1).Scaled(0.6)
+		src := pixel.RGB(1, 0, 0).Scaled(0.4)
+
+		r.Clear(dst)
+		want := cmp.Compose(src, pd.Color(pixel.ZV))
+
+		r.SetComposeMethod(cmp)
+		r.MakeTriangles(quad(pd.Rect, src)).Draw()
+
+		got := pd.Color(pixel.ZV)
+		assert.InDelta(t, want.R, got.R, 1.0/255, "compose method %d", cmp)
+		assert.InDelta(t, want.G, got.G, 1.0/255, "compose method %d", cmp)
+		assert.InDelta(t, want.B, got.B, 1.0/255, "compose method %d", cmp)
+		assert.InDelta(t, want.A, got.A, 1.0/255, "compose method %d", cmp)
+	}
+}
+
+func TestRaster_ClipRect(t *testing.T) {
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	r := pixel.NewRaster(pd)
+
+	tri := quad(pd.Rect, pixel.RGB(1, 1, 1))
+	for i := range *tri {
+		(*tri)[i].ClipRect = pixel.R(0, 0, 2, 4)
+		(*tri)[i].IsClipped = true
+	}
+	r.MakeTriangles(tri).Draw()
+
+	assert.Equal(t, uint8(255), pd.Pix[pd.Index(pixel.V(1, 3))].A)
+	assert.Equal(t, uint8(0), pd.Pix[pd.Index(pixel.V(2, 3))].A)
+}
+
+func TestRaster_Sprite(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 2))
+	pic.Pix[pic.Index(pixel.V(0, 0))] = color.RGBA{R: 255, A: 255}
+	pic.Pix[pic.Index(pixel.V(1, 0))] = color.RGBA{G: 255, A: 255}
+	pic.Pix[pic.Index(pixel.V(0, 1))] = color.RGBA{B: 255, A: 255}
+	pic.Pix[pic.Index(pixel.V(1, 1))] = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	r := pixel.NewRaster(pd)
+
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+	sprite.Draw(r, pixel.IM.Scaled(pixel.ZV, 2).Moved(pd.Rect.Center()))
+
+	for _, tc := range []struct {
+		at   pixel.Vec
+		want color.RGBA
+	}{
+		{pixel.V(0, 0), color.RGBA{R: 255, A: 255}},
+		{pixel.V(1, 1), color.RGBA{R: 255, A: 255}},
+		{pixel.V(3, 0), color.RGBA{G: 255, A: 255}},
+		{pixel.V(0, 3), color.RGBA{B: 255, A: 255}},
+		{pixel.V(2, 2), color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+	} {
+		assert.Equal(t, tc.want, pd.Pix[pd.Index(tc.at)], "pixel %v", tc.at)
+	}
+}
+
+func TestRaster_SpriteAnchorFlipClip(t *testing.T) {
+	pic := pixel.MakePictureData(pixel.R(0, 0, 2, 1))
+	pic.Pix[pic.Index(pixel.V(0, 0))] = color.RGBA{R: 255, A: 255}
+	pic.Pix[pic.Index(pixel.V(1, 0))] = color.RGBA{G: 255, A: 255}
+	red, green := color.RGBA{R: 255, A: 255}, color.RGBA{G: 255, A: 255}
+
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+	assert.Equal(t, pixel.Center, sprite.Anchor())
+	assert.Equal(t, pixel.R(-1, -0.5, 1, 0.5), sprite.Bounds())
+
+	// placed above and to the right of the origin
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	sprite.SetAnchor(pixel.TopRight)
+	assert.Equal(t, pixel.R(0, 0, 2, 1), sprite.Bounds())
+	sprite.Draw(pixel.NewRaster(pd), pixel.IM.Moved(pixel.V(2, 2)))
+	assert.Equal(t, red, pd.Pix[pd.Index(pixel.V(2, 2))])
+	assert.Equal(t, green, pd.Pix[pd.Index(pixel.V(3, 2))])
+	assert.Equal(t, uint8(0), pd.Pix[pd.Index(pixel.V(1, 2))].A)
+
+	// flipped in place
+	pd = pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	sprite.SetFlip(true, false)
+	sprite.Draw(pixel.NewRaster(pd), pixel.IM.Moved(pixel.V(2, 2)))
+	assert.Equal(t, green, pd.Pix[pd.Index(pixel.V(2, 2))])
+	assert.Equal(t, red, pd.Pix[pd.Index(pixel.V(3, 2))])
+
+	// clipped to the left half of the target
+	pd = pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	sprite.SetFlip(false, false)
+	sprite.SetClip(pixel.R(0, 0, 3, 4))
+	sprite.Draw(pixel.NewRaster(pd), pixel.IM.Moved(pixel.V(2, 2)))
+	assert.Equal(t, red, pd.Pix[pd.Index(pixel.V(2, 2))])
+	assert.Equal(t, uint8(0), pd.Pix[pd.Index(pixel.V(3, 2))].A)
+
+	pd = pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	sprite.ClearClip()
+	_, clipped := sprite.Clip()
+	assert.False(t, clipped)
+	sprite.Draw(pixel.NewRaster(pd), pixel.IM.Moved(pixel.V(2, 2)))
+	assert.Equal(t, green, pd.Pix[pd.Index(pixel.V(3, 2))])
+}
+
+func TestRaster_IndexedToPlain(t *testing.T) {
+	red := pixel.RGB(1, 0, 0)
+	indexed := pixel.MakeIndexedTrianglesData(4, []int{0, 1, 2, 0, 2, 3})
+	for i, v := range []pixel.Vec{pixel.V(0, 0), pixel.V(4, 0), pixel.V(4, 4), pixel.V(0, 4)} {
+		indexed.TrianglesData[i].Position = v
+		indexed.TrianglesData[i].Color = red
+	}
+	plain := quad(pixel.R(0, 0, 2, 2), red)
+
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	r := pixel.NewRaster(pd)
+	draw := func(tri pixel.Triangles) {
+		for i := range pd.Pix {
+			pd.Pix[i] = color.RGBA{}
+		}
+		tri.(pixel.TargetTriangles).Draw()
+	}
+
+	rt := r.MakeTriangles(indexed)
+	draw(rt)
+	assert.Equal(t, uint8(255), pd.Pix[pd.Index(pixel.V(3, 3))].R)
+
+	// the indices of the indexed Triangles are forgotten
+	rt.SetLen(plain.Len())
+	rt.Update(plain)
+	draw(rt)
+	assert.Equal(t, uint8(255), pd.Pix[pd.Index(pixel.V(1, 1))].R)
+	assert.Equal(t, uint8(0), pd.Pix[pd.Index(pixel.V(3, 3))].R)
+
+	rt.SetLen(3)
+	rt.Update(plain.Slice(0, 3))
+	draw(rt)
+	assert.Equal(t, uint8(255), pd.Pix[pd.Index(pixel.V(1, 0))].R)
+	assert.Equal(t, uint8(0), pd.Pix[pd.Index(pixel.V(0, 1))].R)
+
+	// slices keep the triangles inside of them
+	rt = r.MakeTriangles(indexed)
+	draw(rt.Slice(0, 3))
+	assert.Equal(t, uint8(255), pd.Pix[pd.Index(pixel.V(3, 0))].R)
+	assert.Equal(t, uint8(0), pd.Pix[pd.Index(pixel.V(0, 3))].R)
+}
+
+func TestRaster_SpriteGolden(t *testing.T) {
+	// a 4x4 picture with a distinct color in each pixel, so that flips and rotations show
+	pic := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	for i := range pic.Pix {
+		pic.Pix[i] = color.RGBA{R: uint8(i % 4 * 85), G: uint8(i / 4 * 85), B: 128, A: 255}
+	}
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+
+	pd := pixeltest.Render(pixel.R(0, 0, 32, 32), func(t pixel.ComposeTarget) {
+		t.(*pixel.Raster).Clear(pixel.RGB(0.2, 0.2, 0.2))
+		sprite.Draw(t, pixel.IM.Scaled(pixel.ZV, 2).Moved(pixel.V(6, 6)))
+		sprite.DrawColorMask(t, pixel.IM.Scaled(pixel.ZV, 2).Rotated(pixel.ZV, 0.5).Moved(pixel.V(24, 8)), pixel.RGB(1, 0.5, 0.5))
+		sprite.SetFlip(true, false)
+		sprite.SetAnchor(pixel.TopRight)
+		sprite.Draw(t, pixel.IM.Scaled(pixel.ZV, 3).Moved(pixel.V(2, 18)))
+		sprite.SetFlip(false, true)
+		sprite.SetClip(pixel.R(18, 18, 26, 26))
+		sprite.Draw(t, pixel.IM.Scaled(pixel.ZV, 3).Moved(pixel.V(18, 18)))
+	})
+	pixeltest.AssertGolden(t, "sprite", pd, pixeltest.Options{Tolerance: 2})
+}
@@ -0,0 +1,182 @@
+package pixel
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ray is a half-line starting at the Origin and going infinitely far in the Direction.
+//
+// The Direction doesn't have to be a unit vector. Distances returned by the Cast methods are
+// always measured in the units of the coordinate system, not in multiples of the Direction.
+//
+//   ray := pixel.Ray{Origin: gun, Direction: target.Sub(gun)}
+//   if hit, ok := ray.CastRect(wall); ok {
+//       fmt.Println(hit.Point, hit.Distance)
+//   }
+type Ray struct {
+	Origin, Direction Vec
+}
+
+// RayHit describes the point where a Ray hit a Shape.
+type RayHit struct {
+	// Point is the position where the Ray hit the Shape.
+	Point Vec
+
+	// Normal is the unit normal of the Shape's surface at the Point. It always faces the Ray,
+	// that is, it points against the Ray's Direction.
+	Normal Vec
+
+	// Distance is the distance from the Ray's Origin to the Point.
+	Distance float64
+}
+
+// String returns the string representation of the Ray.
+//
+//   r := pixel.Ray{Origin: pixel.V(1, 2), Direction: pixel.V(0, 1)}
+//   r.String()     // returns "Ray(Vec(1, 2), Vec(0, 1))"
+//   fmt.Println(r) // Ray(Vec(1, 2), Vec(0, 1))
+func (r Ray) String() string {
+	return fmt.Sprintf("Ray(%v, %v)", r.Origin, r.Direction)
+}
+
+// At returns the point on the Ray at the given distance from its Origin.
+func (r Ray) At(distance float64) Vec {
+	return r.Origin.Add(r.Direction.Unit().Scaled(distance))
+}
+
+// hit creates a RayHit at the given distance along the Ray with the normal turned to face the Ray.
+func (r Ray) hit(distance float64, normal Vec) RayHit {
+	normal = normal.Unit()
+	if normal.Dot(r.Direction) > 0 {
+		normal = normal.Scaled(-1)
+	}
+	return RayHit{
+		Point:    r.At(distance),
+		Normal:   normal,
+		Distance: distance,
+	}
+}
+
+// castSegment returns the distance along the Ray to the segment between a and b.
+func (r Ray) castSegment(a, b Vec) (distance float64, ok bool) {
+	if r.Direction == ZV {
+		return 0, false
+	}
+	d := r.Direction.Unit()
+	e := a.To(b)
+	denom := d.Cross(e)
+	if denom == 0 {
+		return 0, false
+	}
+	oa := r.Origin.To(a)
+	t := oa.Cross(e) / denom
+	s := oa.Cross(d) / denom
+	if t < 0 || s < 0 || s > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// castEdges returns the first hit of the Ray with the closed chain of edges between the vertices.
+func (r Ray) castEdges(vertices []Vec) (RayHit, bool) {
+	var (
+		best   = math.Inf(+1)
+		normal Vec
+	)
+	for i := range vertices {
+		a, b := vertices[i], vertices[(i+1)%len(vertices)]
+		if t, ok := r.castSegment(a, b); ok && t < best {
+			best, normal = t, a.To(b).Normal()
+		}
+	}
+	if math.IsInf(best, +1) {
+		return RayHit{}, false
+	}
+	return r.hit(best, normal), true
+}
+
+// CastLine returns the point where the Ray hits the Line. If the Ray misses the Line, or is
+// parallel to it, this function returns false.
+func (r Ray) CastLine(l Line) (RayHit, bool) {
+	t, ok := r.castSegment(l.A, l.B)
+	if !ok {
+		return RayHit{}, false
+	}
+	return r.hit(t, l.A.To(l.B).Normal()), true
+}
+
+// CastRect returns the first point where the Ray hits the border of the Rect. If the Ray starts
+// inside the Rect, the point where it leaves the Rect is returned.
+func (r Ray) CastRect(rect Rect) (RayHit, bool) {
+	vertices := rect.Norm().Vertices()
+	return r.castEdges(vertices[:])
+}
+
+// CastCircle returns the first point where the Ray hits the circumference of the Circle. If the
+// Ray starts inside the Circle, the point where it leaves the Circle is returned.
+func (r Ray) CastCircle(c Circle) (RayHit, bool) {
+	if r.Direction == ZV {
+		return RayHit{}, false
+	}
+	c = c.Norm()
+	d := r.Direction.Unit()
+	oc := c.Center.To(r.Origin)
+
+	// solve |oc + t*d| = radius for t
+	b := oc.Dot(d)
+	disc := b*b - (oc.SqLen() - c.Radius*c.Radius)
+	if disc < 0 {
+		return RayHit{}, false
+	}
+	sqrt := math.Sqrt(disc)
+	t := -b - sqrt
+	if t < 0 {
+		t = -b + sqrt
+	}
+	if t < 0 {
+		return RayHit{}, false
+	}
+	point := r.Origin.Add(d.Scaled(t))
+	return r.hit(t, c.Center.To(point)), true
+}
+
+// CastPolygon returns the first point where the Ray hits one of the edges of the Polygon. The
+// Polygon doesn't have to be convex.
+func (r Ray) CastPolygon(p Polygon) (RayHit, bool) {
+	return r.castEdges(p)
+}
+
+// Cast returns the first point where the Ray hits the Shape. Rect, Circle, Line and Polygon are
+// supported, Cast panics if passed any other Shape.
+func (r Ray) Cast(s Shape) (RayHit, bool) {
+	switch s := s.(type) {
+	case Rect:
+		return r.CastRect(s)
+	case Circle:
+		return r.CastCircle(s)
+	case Line:
+		return r.CastLine(s)
+	case Polygon:
+		return r.CastPolygon(s)
+	default:
+		panic(fmt.Errorf("(%T).Cast: unsupported Shape %T", r, s))
+	}
+}
+
+// CastNearest casts the Ray against all the Shapes and returns the nearest hit along with the
+// index of the Shape that was hit. If no Shape was hit, this function returns false.
+//
+//   if hit, i, ok := ray.CastNearest(obstacles); ok {
+//       fmt.Printf("hit obstacle %d at %v\n", i, hit.Point)
+//   }
+func (r Ray) CastNearest(shapes []Shape) (hit RayHit, index int, ok bool) {
+	index = -1
+	for i, s := range shapes {
+		h, hitOk := r.Cast(s)
+		if hitOk && (!ok || h.Distance < hit.Distance) {
+			hit, index, ok = h, i, true
+		}
+	}
+	return hit, index, ok
+}
@@ -0,0 +1,123 @@
+package pixel_test
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRay_Cast(t *testing.T) {
+	tests := []struct {
+		name   string
+		ray    pixel.Ray
+		shape  pixel.Shape
+		ok     bool
+		point  pixel.Vec
+		normal pixel.Vec
+		dist   float64
+	}{
+		{
+			name:  "line",
+			ray:   pixel.Ray{Origin: pixel.V(0, 0), Direction: pixel.V(1, 0)},
+			shape: pixel.L(pixel.V(5, -5), pixel.V(5, 5)),
+			ok:    true, point: pixel.V(5, 0), normal: pixel.V(-1, 0), dist: 5,
+		},
+		{
+			name:  "line missed",
+			ray:   pixel.Ray{Origin: pixel.V(0, 0), Direction: pixel.V(1, 0)},
+			shape: pixel.L(pixel.V(5, 1), pixel.V(5, 5)),
+		},
+		{
+			name:  "line behind",
+			ray:   pixel.Ray{Origin: pixel.V(0, 0), Direction: pixel.V(-1, 0)},
+			shape: pixel.L(pixel.V(5, -5), pixel.V(5, 5)),
+		},
+		{
+			name:  "line parallel",
+			ray:   pixel.Ray{Origin: pixel.V(0, 0), Direction: pixel.V(0, 1)},
+			shape: pixel.L(pixel.V(5, -5), pixel.V(5, 5)),
+		},
+		{
+			name:  "rect",
+			ray:   pixel.Ray{Origin: pixel.V(5, -10), Direction: pixel.V(0, 2)},
+			shape: pixel.R(0, 0, 10, 10),
+			ok:    true, point: pixel.V(5, 0), normal: pixel.V(0, -1), dist: 10,
+		},
+		{
+			name:  "rect from inside",
+			ray:   pixel.Ray{Origin: pixel.V(5, 5), Direction: pixel.V(1, 0)},
+			shape: pixel.R(0, 0, 10, 10),
+			ok:    true, point: pixel.V(10, 5), normal: pixel.V(-1, 0), dist: 5,
+		},
+		{
+			name:  "rect corner",
+			ray:   pixel.Ray{Origin: pixel.V(-1, 9), Direction: pixel.V(1, 1)},
+			shape: pixel.R(0, 0, 10, 10),
+			ok:    true, point: pixel.V(0, 10), dist: 1.4142135623730951,
+		},
+		{
+			name:  "circle",
+			ray:   pixel.Ray{Origin: pixel.V(-10, 0), Direction: pixel.V(1, 0)},
+			shape: pixel.C(pixel.V(0, 0), 3),
+			ok:    true, point: pixel.V(-3, 0), normal: pixel.V(-1, 0), dist: 7,
+		},
+		{
+			name:  "circle from inside",
+			ray:   pixel.Ray{Origin: pixel.V(0, 0), Direction: pixel.V(0, 1)},
+			shape: pixel.C(pixel.V(0, 0), 3),
+			ok:    true, point: pixel.V(0, 3), normal: pixel.V(0, -1), dist: 3,
+		},
+		{
+			name:  "circle missed",
+			ray:   pixel.Ray{Origin: pixel.V(-10, 4), Direction: pixel.V(1, 0)},
+			shape: pixel.C(pixel.V(0, 0), 3),
+		},
+		{
+			name:  "concave polygon",
+			ray:   pixel.Ray{Origin: pixel.V(5, 20), Direction: pixel.V(0, -1)},
+			shape: pixel.Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(5, 3), pixel.V(0, 10)},
+			ok:    true, point: pixel.V(5, 3), dist: 17,
+		},
+		{
+			name:  "zero direction",
+			ray:   pixel.Ray{Origin: pixel.V(-10, 0)},
+			shape: pixel.C(pixel.V(0, 0), 3),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := tt.ray.Cast(tt.shape)
+			assert.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+			assertVecInDelta(t, tt.point, hit.Point, 1e-9)
+			assert.InDelta(t, tt.dist, hit.Distance, 1e-9)
+			assert.InDelta(t, 1, hit.Normal.Len(), 1e-9)
+			assert.LessOrEqual(t, hit.Normal.Dot(tt.ray.Direction), 0.0)
+			if tt.normal != pixel.ZV {
+				assertVecInDelta(t, tt.normal, hit.Normal, 1e-9)
+			}
+		})
+	}
+}
+
+func TestRay_CastNearest(t *testing.T) {
+	ray := pixel.Ray{Origin: pixel.V(0, 0), Direction: pixel.V(1, 0)}
+	shapes := []pixel.Shape{
+		pixel.R(20, -1, 30, 1),
+		pixel.C(pixel.V(10, 0), 2),
+		pixel.L(pixel.V(-5, -5), pixel.V(-5, 5)),
+		pixel.Polygon{pixel.V(15, -5), pixel.V(16, 5), pixel.V(14, 5)},
+	}
+
+	hit, i, ok := ray.CastNearest(shapes)
+	assert.True(t, ok)
+	assert.Equal(t, 1, i)
+	assertVecInDelta(t, pixel.V(8, 0), hit.Point, 1e-9)
+
+	_, i, ok = pixel.Ray{Origin: pixel.V(0, 10), Direction: pixel.V(1, 0)}.CastNearest(shapes)
+	assert.False(t, ok)
+	assert.Equal(t, -1, i)
+}
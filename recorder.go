@@ -0,0 +1,590 @@
+package pixel
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"image/color"
+	"reflect"
+)
+
+// Recorder is a ComposeTarget which doesn't draw anything. Instead, it records all calls made on
+// it, the Triangles and Pictures made by it and their draws into a DisplayList, which can be
+// replayed onto any other Target later, serialized and compared with another DisplayList.
+//
+// This is useful for caching static layers, such as a UI which rarely changes, for inspecting
+// frames offline and for testing rendering code without OpenGL.
+//
+//   rec := pixel.NewRecorder()
+//   drawUI(rec)
+//   ui := rec.DisplayList()
+//   for !win.Closed() {
+//       ui.Replay(win)
+//       win.Update()
+//   }
+type Recorder struct {
+	list DisplayList
+	tris int
+	gen  int
+}
+
+var _ ComposeTarget = (*Recorder)(nil)
+
+// NewRecorder creates a new Recorder with an empty DisplayList.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// DisplayList returns the DisplayList of everything recorded since the creation of the Recorder or
+// the last call to Reset. Further recording doesn't change the returned DisplayList.
+func (r *Recorder) DisplayList() *DisplayList {
+	return &DisplayList{
+		Ops:      r.list.Ops[:len(r.list.Ops):len(r.list.Ops)],
+		Pictures: r.list.Pictures[:len(r.list.Pictures):len(r.list.Pictures)],
+	}
+}
+
+// Reset starts recording a new DisplayList.
+//
+// Triangles and Pictures made by the Recorder before can still be drawn onto it. They're recorded
+// into the new DisplayList again when drawn for the first time after Reset.
+func (r *Recorder) Reset() {
+	r.list = DisplayList{}
+	r.tris = 0
+	r.gen++
+}
+
+// MakeTriangles returns a specialized copy of the provided Triangles that draws onto this Recorder.
+//
+// TrianglesPosition, TrianglesColor, TrianglesPicture, TrianglesClipped and TrianglesIndexed are
+// recorded.
+func (r *Recorder) MakeTriangles(t Triangles) TargetTriangles {
+	rt := &recorderTriangles{
+		TrianglesData: MakeTrianglesData(t.Len()),
+		dst:           r,
+	}
+	rt.Update(t)
+	rt.record()
+	return rt
+}
+
+// MakePicture returns a specialized copy of the provided Picture that draws onto this Recorder.
+// Any Picture is accepted.
+//
+// The Picture itself is recorded, not its content, so its changes after recording affect the
+// replays. The content is only copied when the DisplayList is serialized.
+func (r *Recorder) MakePicture(p Picture) TargetPicture {
+	if rp, ok := p.(*recorderPicture); ok {
+		p = rp.pic
+	}
+	rp := &recorderPicture{pic: p, dst: r}
+	rp.record()
+	return rp
+}
+
+// SetMatrix records setting a Matrix that every point will be projected by.
+func (r *Recorder) SetMatrix(m Matrix) {
+	r.add(DisplayOp{Kind: DisplaySetMatrix, Matrix: m})
+}
+
+// SetColorMask records setting a color mask. Nil color mask is recorded as a fully opaque white.
+func (r *Recorder) SetColorMask(c color.Color) {
+	if c == nil {
+		c = Alpha(1)
+	}
+	r.add(DisplayOp{Kind: DisplaySetColorMask, Color: ToRGBA(c)})
+}
+
+// SetComposeMethod records setting a Porter-Duff composition method or a blend mode.
+func (r *Recorder) SetComposeMethod(cmp ComposeMethod) {
+	r.add(DisplayOp{Kind: DisplaySetComposeMethod, ComposeMethod: cmp})
+}
+
+func (r *Recorder) add(op DisplayOp) {
+	r.list.Ops = append(r.list.Ops, op)
+}
+
+type recorderTriangles struct {
+	*TrianglesData
+	indices []int
+	indexed bool
+	dst     *Recorder
+
+	// id is the number of the Triangles in the DisplayList of the Recorder's generation gen,
+	// dirty Triangles are recorded again when drawn
+	id    int
+	gen   int
+	dirty bool
+	made  bool
+}
+
+func (rt *recorderTriangles) SetLen(len int) {
+	if rt.indexed && len < rt.Len() {
+		rt.indices = sliceIndices(rt.indices, 0, len)
+	}
+	rt.TrianglesData.SetLen(len)
+	rt.dirty = true
+}
+
+func (rt *recorderTriangles) Slice(i, j int) Triangles {
+	st := &recorderTriangles{
+		TrianglesData: rt.TrianglesData.Slice(i, j).(*TrianglesData),
+		indexed:       rt.indexed,
+		dst:           rt.dst,
+	}
+	if rt.indexed {
+		st.indices = sliceIndices(rt.indices, i, j)
+	}
+	return st
+}
+
+func (rt *recorderTriangles) Update(t Triangles) {
+	rt.TrianglesData.Update(t)
+	indices, indexed := indicesOf(t)
+	rt.indices, rt.indexed = append(rt.indices[:0], indices...), indexed
+	rt.dirty = true
+}
+
+func (rt *recorderTriangles) Copy() Triangles {
+	return &recorderTriangles{
+		TrianglesData: rt.TrianglesData.Copy().(*TrianglesData),
+		indices:       append([]int(nil), rt.indices...),
+		indexed:       rt.indexed,
+		dst:           rt.dst,
+	}
+}
+
+func (rt *recorderTriangles) Indices() (indices []int, is bool) {
+	return rt.indices, rt.indexed
+}
+
+// vertices returns an independent copy of the current vertices.
+func (rt *recorderTriangles) vertices() Triangles {
+	if rt.indexed {
+		itd := MakeIndexedTrianglesData(rt.Len(), nil)
+		itd.Update(rt)
+		return itd
+	}
+	return rt.TrianglesData.Copy()
+}
+
+// record records making the Triangles if they're not in the current DisplayList yet, or updating
+// them if they changed.
+func (rt *recorderTriangles) record() {
+	r := rt.dst
+	switch {
+	case !rt.made || rt.gen != r.gen:
+		rt.id, rt.gen, rt.made = r.tris, r.gen, true
+		r.tris++
+		r.add(DisplayOp{Kind: DisplayMakeTriangles, Triangles: rt.id, Vertices: rt.vertices()})
+	case rt.dirty:
+		r.add(DisplayOp{Kind: DisplayUpdateTriangles, Triangles: rt.id, Vertices: rt.vertices()})
+	}
+	rt.dirty = false
+}
+
+func (rt *recorderTriangles) Draw() {
+	rt.record()
+	rt.dst.add(DisplayOp{Kind: DisplayDraw, Triangles: rt.id, Picture: -1})
+}
+
+type recorderPicture struct {
+	pic Picture
+	dst *Recorder
+
+	id   int
+	gen  int
+	made bool
+}
+
+func (rp *recorderPicture) Bounds() Rect {
+	return rp.pic.Bounds()
+}
+
+// record records making the Picture if it's not in the current DisplayList yet.
+func (rp *recorderPicture) record() {
+	r := rp.dst
+	if rp.made && rp.gen == r.gen {
+		return
+	}
+	rp.id, rp.gen, rp.made = len(r.list.Pictures), r.gen, true
+	r.list.Pictures = append(r.list.Pictures, rp.pic)
+	r.add(DisplayOp{Kind: DisplayMakePicture, Picture: rp.id})
+}
+
+func (rp *recorderPicture) Draw(t TargetTriangles) {
+	rt := t.(*recorderTriangles)
+	if rp.dst != rt.dst {
+		panic(fmt.Errorf("(%T).Draw: TargetTriangles generated by different Recorder", rp))
+	}
+	rt.record()
+	rp.record()
+	rp.dst.add(DisplayOp{Kind: DisplayDraw, Triangles: rt.id, Picture: rp.id})
+}
+
+// DisplayOpKind is the kind of a call recorded in a DisplayOp.
+type DisplayOpKind int
+
+// Here's the list of all kinds of recorded calls.
+const (
+	// DisplayMakeTriangles makes new Triangles with the Vertices.
+	DisplayMakeTriangles DisplayOpKind = iota
+
+	// DisplayUpdateTriangles sets the length and the content of the Triangles to the Vertices.
+	DisplayUpdateTriangles
+
+	// DisplayMakePicture makes the Picture.
+	DisplayMakePicture
+
+	// DisplayDraw draws the Triangles with the Picture, or without a Picture if it's -1.
+	DisplayDraw
+
+	// DisplaySetMatrix sets the Matrix of the Target.
+	DisplaySetMatrix
+
+	// DisplaySetColorMask sets the color mask of the Target to the Color.
+	DisplaySetColorMask
+
+	// DisplaySetComposeMethod sets the ComposeMethod of the Target.
+	DisplaySetComposeMethod
+)
+
+// String returns the name of the DisplayOpKind.
+func (k DisplayOpKind) String() string {
+	switch k {
+	case DisplayMakeTriangles:
+		return "MakeTriangles"
+	case DisplayUpdateTriangles:
+		return "UpdateTriangles"
+	case DisplayMakePicture:
+		return "MakePicture"
+	case DisplayDraw:
+		return "Draw"
+	case DisplaySetMatrix:
+		return "SetMatrix"
+	case DisplaySetColorMask:
+		return "SetColorMask"
+	case DisplaySetComposeMethod:
+		return "SetComposeMethod"
+	default:
+		return fmt.Sprintf("DisplayOpKind(%d)", int(k))
+	}
+}
+
+// DisplayOp is a single call recorded in a DisplayList. Only the fields used by its Kind are set.
+type DisplayOp struct {
+	Kind DisplayOpKind
+
+	// Triangles is the number of the Triangles made, updated or drawn. Triangles are numbered in
+	// the order they're made, starting from 0.
+	Triangles int
+
+	// Picture is the index of the Picture made or drawn in the Pictures of the DisplayList. It's -1
+	// for a Draw without a Picture.
+	Picture int
+
+	// Vertices is the content of the Triangles, *TrianglesData or *IndexedTrianglesData.
+	Vertices Triangles
+
+	Matrix        Matrix
+	Color         RGBA
+	ComposeMethod ComposeMethod
+}
+
+// String returns a short description of the DisplayOp.
+//
+//   op.String() // returns "Draw(Triangles 0, Picture 1)"
+func (op DisplayOp) String() string {
+	switch op.Kind {
+	case DisplayMakeTriangles, DisplayUpdateTriangles:
+		n := 0
+		if op.Vertices != nil {
+			n = op.Vertices.Len()
+		}
+		return fmt.Sprintf("%v(Triangles %d, %d vertices)", op.Kind, op.Triangles, n)
+	case DisplayMakePicture:
+		return fmt.Sprintf("%v(Picture %d)", op.Kind, op.Picture)
+	case DisplayDraw:
+		if op.Picture < 0 {
+			return fmt.Sprintf("%v(Triangles %d)", op.Kind, op.Triangles)
+		}
+		return fmt.Sprintf("%v(Triangles %d, Picture %d)", op.Kind, op.Triangles, op.Picture)
+	case DisplaySetMatrix:
+		return fmt.Sprintf("%v(%v)", op.Kind, op.Matrix)
+	case DisplaySetColorMask:
+		return fmt.Sprintf("%v(%v)", op.Kind, op.Color)
+	case DisplaySetComposeMethod:
+		return fmt.Sprintf("%v(%d)", op.Kind, int(op.ComposeMethod))
+	default:
+		return op.Kind.String()
+	}
+}
+
+// DisplayList is a list of calls made on a Target recorded by a Recorder.
+//
+// The DisplayList can be replayed onto any Target, serialized using MarshalBinary and
+// UnmarshalBinary and compared with another DisplayList using Diff.
+type DisplayList struct {
+	Ops      []DisplayOp
+	Pictures []Picture
+}
+
+// Replay makes all recorded calls on the Target, in the order they were recorded.
+//
+// SetMatrix and SetColorMask are only replayed if the Target is a BasicTarget, SetComposeMethod
+// is only replayed if it's a ComposeTarget.
+//
+// The Triangles and Pictures are made by the Target again on each Replay. To draw a static
+// DisplayList onto the same Target repeatedly, replay it onto a Batch or a MultiBatch once and
+// draw that one instead.
+func (dl *DisplayList) Replay(t Target) {
+	var (
+		tris = make(map[int]TargetTriangles)
+		pics = make(map[int]TargetPicture)
+	)
+	for i, op := range dl.Ops {
+		switch op.Kind {
+		case DisplayMakeTriangles:
+			tri := t.MakeTriangles(op.Vertices)
+			if _, supported := tri.(TrianglesIndexed); !supported {
+				replayUpdate(tri, op.Vertices)
+			}
+			tris[op.Triangles] = tri
+		case DisplayUpdateTriangles:
+			tri := tris[op.Triangles]
+			if tri == nil {
+				panic(fmt.Errorf("(%T).Replay: op %d: unknown Triangles %d", dl, i, op.Triangles))
+			}
+			replayUpdate(tri, op.Vertices)
+		case DisplayMakePicture:
+			if op.Picture < 0 || op.Picture >= len(dl.Pictures) {
+				panic(fmt.Errorf("(%T).Replay: op %d: unknown Picture %d", dl, i, op.Picture))
+			}
+			pics[op.Picture] = t.MakePicture(dl.Pictures[op.Picture])
+		case DisplayDraw:
+			tri := tris[op.Triangles]
+			if tri == nil {
+				panic(fmt.Errorf("(%T).Replay: op %d: unknown Triangles %d", dl, i, op.Triangles))
+			}
+			if op.Picture < 0 {
+				tri.Draw()
+				break
+			}
+			pic := pics[op.Picture]
+			if pic == nil {
+				panic(fmt.Errorf("(%T).Replay: op %d: unknown Picture %d", dl, i, op.Picture))
+			}
+			pic.Draw(tri)
+		case DisplaySetMatrix:
+			if bt, ok := t.(BasicTarget); ok {
+				bt.SetMatrix(op.Matrix)
+			}
+		case DisplaySetColorMask:
+			if bt, ok := t.(BasicTarget); ok {
+				bt.SetColorMask(op.Color)
+			}
+		case DisplaySetComposeMethod:
+			if ct, ok := t.(ComposeTarget); ok {
+				ct.SetComposeMethod(op.ComposeMethod)
+			}
+		default:
+			panic(fmt.Errorf("(%T).Replay: op %d: invalid DisplayOpKind", dl, i))
+		}
+	}
+}
+
+// replayUpdate updates the Triangles to the vertices, duplicating the shared vertices if the
+// Triangles don't support TrianglesIndexed, just like Drawer does.
+func replayUpdate(tri TargetTriangles, vertices Triangles) {
+	_, supported := tri.(TrianglesIndexed)
+	if indices, indexed := indicesOf(vertices); indexed && !supported {
+		vertices = unindex(nil, vertices, indices)
+	}
+	tri.SetLen(vertices.Len())
+	tri.Update(vertices)
+}
+
+// Diff compares the DisplayList with another one and returns a description of each differing op.
+// If the DisplayLists are equal, Diff returns nil.
+//
+// Pictures are equal if they're the same Picture or if they have the same bounds and pixels.
+func (dl *DisplayList) Diff(other *DisplayList) []string {
+	var diffs []string
+	n := len(dl.Ops)
+	if len(other.Ops) > n {
+		n = len(other.Ops)
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(other.Ops):
+			diffs = append(diffs, fmt.Sprintf("op %d: %v != none", i, dl.Ops[i]))
+		case i >= len(dl.Ops):
+			diffs = append(diffs, fmt.Sprintf("op %d: none != %v", i, other.Ops[i]))
+		default:
+			if diff := dl.diffOp(other, i); diff != "" {
+				diffs = append(diffs, fmt.Sprintf("op %d: %s", i, diff))
+			}
+		}
+	}
+	return diffs
+}
+
+// diffOp describes the difference of the i-th ops of the DisplayLists, or returns "" if they're
+// equal.
+func (dl *DisplayList) diffOp(other *DisplayList, i int) string {
+	a, b := dl.Ops[i], other.Ops[i]
+	if a.String() != b.String() {
+		return fmt.Sprintf("%v != %v", a, b)
+	}
+	switch a.Kind {
+	case DisplayMakeTriangles, DisplayUpdateTriangles:
+		ta, tb := verticesOf(a.Vertices), verticesOf(b.Vertices)
+		for j := range ta {
+			if ta[j] != tb[j] {
+				return fmt.Sprintf("%v: vertex %d: %+v != %+v", a, j, ta[j], tb[j])
+			}
+		}
+		ia, _ := indicesOf(a.Vertices)
+		ib, _ := indicesOf(b.Vertices)
+		if !reflect.DeepEqual(ia, ib) && (len(ia) > 0 || len(ib) > 0) {
+			return fmt.Sprintf("%v: indices %v != %v", a, ia, ib)
+		}
+	case DisplayMakePicture:
+		if !picturesEqual(dl.Pictures[a.Picture], other.Pictures[b.Picture]) {
+			return fmt.Sprintf("%v: different Pictures", a)
+		}
+	}
+	return ""
+}
+
+// verticesOf returns the vertices of *TrianglesData or *IndexedTrianglesData.
+func verticesOf(t Triangles) TrianglesData {
+	switch t := t.(type) {
+	case *TrianglesData:
+		return *t
+	case *IndexedTrianglesData:
+		return t.TrianglesData
+	}
+	return nil
+}
+
+// picturesEqual returns whether the Pictures are the same or have the same bounds and pixels.
+func picturesEqual(a, b Picture) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	pa, pb := PictureDataFromPicture(a), PictureDataFromPicture(b)
+	return pa.Rect == pb.Rect && reflect.DeepEqual(pa.Pix, pb.Pix)
+}
+
+// displayListData is the serialized form of a DisplayList.
+type displayListData struct {
+	Ops []displayOpData
+
+	// Pictures are the indices of the Pictures of the DisplayList in Images, the same Picture is
+	// serialized only once
+	Pictures []int
+	Images   []*PictureData
+}
+
+type displayOpData struct {
+	Kind          DisplayOpKind
+	Triangles     int
+	Picture       int
+	Vertices      TrianglesData
+	Indices       []int
+	Indexed       bool
+	Matrix        Matrix
+	Color         RGBA
+	ComposeMethod ComposeMethod
+}
+
+// MarshalBinary serializes the DisplayList. The content of its Pictures is converted to
+// PictureData.
+func (dl *DisplayList) MarshalBinary() ([]byte, error) {
+	var data displayListData
+
+	images := make(map[Picture]int)
+	for _, pic := range dl.Pictures {
+		index, ok := images[pic]
+		if !ok {
+			index = len(data.Images)
+			images[pic] = index
+			data.Images = append(data.Images, PictureDataFromPicture(pic))
+		}
+		data.Pictures = append(data.Pictures, index)
+	}
+
+	for _, op := range dl.Ops {
+		opData := displayOpData{
+			Kind:          op.Kind,
+			Triangles:     op.Triangles,
+			Picture:       op.Picture,
+			Matrix:        op.Matrix,
+			Color:         op.Color,
+			ComposeMethod: op.ComposeMethod,
+		}
+		if op.Vertices != nil {
+			opData.Vertices = verticesOf(op.Vertices)
+			opData.Indices, opData.Indexed = indicesOf(op.Vertices)
+		}
+		data.Ops = append(data.Ops, opData)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, fmt.Errorf("(%T).MarshalBinary: %v", dl, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a DisplayList serialized by MarshalBinary. The Pictures of the restored
+// DisplayList are PictureData.
+func (dl *DisplayList) UnmarshalBinary(b []byte) error {
+	var data displayListData
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&data); err != nil {
+		return fmt.Errorf("(%T).UnmarshalBinary: %v", dl, err)
+	}
+
+	pics := make([]Picture, len(data.Pictures))
+	for i, index := range data.Pictures {
+		if index < 0 || index >= len(data.Images) {
+			return fmt.Errorf("(%T).UnmarshalBinary: invalid Picture %d", dl, i)
+		}
+		pics[i] = data.Images[index]
+	}
+
+	ops := make([]DisplayOp, len(data.Ops))
+	for i, opData := range data.Ops {
+		ops[i] = DisplayOp{
+			Kind:          opData.Kind,
+			Triangles:     opData.Triangles,
+			Picture:       opData.Picture,
+			Matrix:        opData.Matrix,
+			Color:         opData.Color,
+			ComposeMethod: opData.ComposeMethod,
+		}
+		switch opData.Kind {
+		case DisplayMakeTriangles, DisplayUpdateTriangles:
+			vertices := opData.Vertices
+			if vertices == nil {
+				vertices = TrianglesData{}
+			}
+			if opData.Indexed {
+				ops[i].Vertices = &IndexedTrianglesData{TrianglesData: vertices, indices: opData.Indices}
+			} else {
+				ops[i].Vertices = &vertices
+			}
+		case DisplayMakePicture, DisplayDraw:
+			if opData.Picture >= len(pics) {
+				return fmt.Errorf("(%T).UnmarshalBinary: op %d: invalid Picture %d", dl, i, opData.Picture)
+			}
+		}
+	}
+
+	dl.Ops, dl.Pictures = ops, pics
+	return nil
+}
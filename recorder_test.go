@@ -0,0 +1,81 @@
+package pixel_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	sprite := pixel.NewSprite(solidPicture(red), pixel.R(0, 0, 2, 2))
+	draw := func(t pixel.ComposeTarget) {
+		t.SetComposeMethod(pixel.ComposeOver)
+		sprite.Draw(t, pixel.IM.Moved(pixel.V(1, 1)))
+		t.SetMatrix(pixel.IM.Moved(pixel.V(2, 2)))
+		t.SetColorMask(pixel.Alpha(0.5))
+		sprite.Draw(t, pixel.IM.Moved(pixel.V(1, 1)))
+	}
+	raster := func(dl *pixel.DisplayList) *pixel.PictureData {
+		pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+		dl.Replay(pixel.NewRaster(pd))
+		return pd
+	}
+
+	rec := pixel.NewRecorder()
+	draw(rec)
+	dl := rec.DisplayList()
+	var kinds []pixel.DisplayOpKind
+	for _, op := range dl.Ops {
+		kinds = append(kinds, op.Kind)
+	}
+	assert.Equal(t, []pixel.DisplayOpKind{
+		pixel.DisplaySetComposeMethod,
+		pixel.DisplayMakeTriangles,
+		pixel.DisplayMakePicture,
+		pixel.DisplayDraw,
+		pixel.DisplaySetMatrix,
+		pixel.DisplaySetColorMask,
+		pixel.DisplayDraw,
+	}, kinds)
+
+	// replaying gives the same result as drawing directly
+	want := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	draw(pixel.NewRaster(want))
+	assert.Equal(t, want.Pix, raster(dl).Pix)
+
+	// serialized DisplayList is equal to the original one
+	b, err := dl.MarshalBinary()
+	assert.NoError(t, err)
+	var restored pixel.DisplayList
+	assert.NoError(t, restored.UnmarshalBinary(b))
+	assert.Nil(t, dl.Diff(&restored))
+	assert.Equal(t, want.Pix, raster(&restored).Pix)
+
+	// after Reset, the Triangles are made again and their changed vertices are reported by Diff
+	rec.Reset()
+	sprite.Set(sprite.Picture(), pixel.R(0, 0, 1, 1))
+	draw(rec)
+	changed := rec.DisplayList()
+	assert.Equal(t, pixel.DisplayMakeTriangles, changed.Ops[1].Kind)
+	assert.Equal(t, []string{
+		"op 1: MakeTriangles(Triangles 0, 4 vertices): vertex 0: " +
+			"{Position:Vec(0, 0) Color:{R:1 G:1 B:1 A:1} Picture:Vec(0, 0) Intensity:1 ClipRect:Rect(0, 0, 0, 0) IsClipped:false} != " +
+			"{Position:Vec(0.5, 0.5) Color:{R:1 G:1 B:1 A:1} Picture:Vec(0, 0) Intensity:1 ClipRect:Rect(0, 0, 0, 0) IsClipped:false}",
+	}, dl.Diff(changed))
+
+	// changed Triangles are recorded as an update
+	sprite.Set(sprite.Picture(), pixel.R(0, 0, 2, 2))
+	sprite.Draw(rec, pixel.IM)
+	assert.Len(t, rec.DisplayList().Ops, len(changed.Ops)+2)
+	assert.Equal(t, pixel.DisplayUpdateTriangles, rec.DisplayList().Ops[len(changed.Ops)].Kind)
+
+	// updating from plain Triangles forgets the indices
+	tri := rec.MakeTriangles(pixel.MakeIndexedTrianglesData(4, []int{0, 1, 2, 0, 2, 3}))
+	tri.SetLen(3)
+	tri.Update(pixel.MakeTrianglesData(3))
+	_, indexed := tri.(pixel.TrianglesIndexed).Indices()
+	assert.False(t, indexed)
+}
@@ -50,6 +50,11 @@ func (r Rect) Norm() Rect {
 	}
 }
 
+// Bounds returns the Rect in normal form. It makes Rect a Shape.
+func (r Rect) Bounds() Rect {
+	return r.Norm()
+}
+
 // W returns the width of the Rect.
 func (r Rect) W() float64 {
 	return r.Max.X - r.Min.X
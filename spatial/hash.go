@@ -0,0 +1,286 @@
+package spatial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Hash is an Index which divides the plane into an infinite grid of square cells and remembers
+// which items overlap each cell. Only the cells which contain any items take memory.
+//
+// Hash is the fastest Index for items of similar sizes spread over a big or unbounded world. The
+// cell size should be roughly the size of a typical item. Items much bigger than a cell take up
+// many cells, which makes them slow to insert, move and remove.
+type Hash struct {
+	cellSize float64
+	cells    map[hashCell][]*hashEntry
+	items    map[interface{}]*hashEntry
+	query    uint64 // incremented by each query to mark the entries already found
+}
+
+var _ Index = (*Hash)(nil)
+
+type hashCell struct {
+	x, y int
+}
+
+type hashEntry struct {
+	item     interface{}
+	bounds   pixel.Rect
+	min, max hashCell
+	query    uint64
+}
+
+// NewHash creates a new empty Hash with the given size of its cells. It panics if the size is
+// not positive.
+func NewHash(cellSize float64) *Hash {
+	if cellSize <= 0 {
+		panic(fmt.Errorf("spatial.NewHash: cell size must be positive, got %v", cellSize))
+	}
+	return &Hash{
+		cellSize: cellSize,
+		cells:    make(map[hashCell][]*hashEntry),
+		items:    make(map[interface{}]*hashEntry),
+	}
+}
+
+// CellSize returns the size of the cells of the Hash.
+func (h *Hash) CellSize() float64 {
+	return h.cellSize
+}
+
+func (h *Hash) cellAt(v pixel.Vec) hashCell {
+	return hashCell{
+		x: int(math.Floor(v.X / h.cellSize)),
+		y: int(math.Floor(v.Y / h.cellSize)),
+	}
+}
+
+// Insert adds the item with the bounds to the Hash. It panics if the item already is in the Hash.
+func (h *Hash) Insert(item interface{}, bounds pixel.Rect) {
+	if _, ok := h.items[item]; ok {
+		panic(fmt.Errorf("(%T).Insert: item %v already inserted", h, item))
+	}
+	e := &hashEntry{item: item}
+	h.items[item] = e
+	h.place(e, bounds.Norm())
+}
+
+// Move changes the bounds of the item. It panics if the item isn't in the Hash.
+func (h *Hash) Move(item interface{}, bounds pixel.Rect) {
+	e, ok := h.items[item]
+	if !ok {
+		panic(fmt.Errorf("(%T).Move: item %v not inserted", h, item))
+	}
+	bounds = bounds.Norm()
+	if h.cellAt(bounds.Min) == e.min && h.cellAt(bounds.Max) == e.max {
+		e.bounds = bounds
+		return
+	}
+	h.unplace(e)
+	h.place(e, bounds)
+}
+
+// Remove removes the item from the Hash. It does nothing if the item isn't in the Hash.
+func (h *Hash) Remove(item interface{}) {
+	e, ok := h.items[item]
+	if !ok {
+		return
+	}
+	delete(h.items, item)
+	h.unplace(e)
+}
+
+func (h *Hash) place(e *hashEntry, bounds pixel.Rect) {
+	e.bounds = bounds
+	e.min, e.max = h.cellAt(bounds.Min), h.cellAt(bounds.Max)
+	for x := e.min.x; x <= e.max.x; x++ {
+		for y := e.min.y; y <= e.max.y; y++ {
+			c := hashCell{x, y}
+			h.cells[c] = append(h.cells[c], e)
+		}
+	}
+}
+
+func (h *Hash) unplace(e *hashEntry) {
+	for x := e.min.x; x <= e.max.x; x++ {
+		for y := e.min.y; y <= e.max.y; y++ {
+			c := hashCell{x, y}
+			entries := h.cells[c]
+			for i, other := range entries {
+				if other == e {
+					last := len(entries) - 1
+					entries[i], entries[last] = entries[last], nil
+					entries = entries[:last]
+					break
+				}
+			}
+			if len(entries) == 0 {
+				delete(h.cells, c)
+			} else {
+				h.cells[c] = entries
+			}
+		}
+	}
+}
+
+// Bounds returns the normalized bounds of the item and whether it's in the Hash.
+func (h *Hash) Bounds(item interface{}) (pixel.Rect, bool) {
+	e, ok := h.items[item]
+	if !ok {
+		return pixel.Rect{}, false
+	}
+	return e.bounds, true
+}
+
+// Len returns the number of items in the Hash.
+func (h *Hash) Len() int {
+	return len(h.items)
+}
+
+// Clear removes all items from the Hash.
+func (h *Hash) Clear() {
+	h.cells = make(map[hashCell][]*hashEntry)
+	h.items = make(map[interface{}]*hashEntry)
+}
+
+// visit calls found once for each entry in the cells between min and max whose bounds pass the
+// test.
+func (h *Hash) visit(min, max hashCell, test func(pixel.Rect) bool, found func(*hashEntry)) {
+	h.query++
+	check := func(entries []*hashEntry) {
+		for _, e := range entries {
+			if e.query == h.query {
+				continue
+			}
+			e.query = h.query
+			if test(e.bounds) {
+				found(e)
+			}
+		}
+	}
+
+	// for huge areas, it's faster to go through the occupied cells than through the whole area
+	if float64(max.x-min.x+1)*float64(max.y-min.y+1) > float64(len(h.cells)) {
+		for c, entries := range h.cells {
+			if min.x <= c.x && c.x <= max.x && min.y <= c.y && c.y <= max.y {
+				check(entries)
+			}
+		}
+		return
+	}
+	for x := min.x; x <= max.x; x++ {
+		for y := min.y; y <= max.y; y++ {
+			check(h.cells[hashCell{x, y}])
+		}
+	}
+}
+
+// QueryRect returns all items whose bounds intersect the Rect.
+func (h *Hash) QueryRect(r pixel.Rect) []interface{} {
+	r = r.Norm()
+	var items []interface{}
+	h.visit(h.cellAt(r.Min), h.cellAt(r.Max),
+		func(bounds pixel.Rect) bool { return overlaps(bounds, r) },
+		func(e *hashEntry) { items = append(items, e.item) },
+	)
+	return items
+}
+
+// QueryCircle returns all items whose bounds intersect the Circle.
+func (h *Hash) QueryCircle(c pixel.Circle) []interface{} {
+	c = c.Norm()
+	var items []interface{}
+	bounds := c.Bounds()
+	h.visit(h.cellAt(bounds.Min), h.cellAt(bounds.Max),
+		func(bounds pixel.Rect) bool { return overlapsCircle(bounds, c) },
+		func(e *hashEntry) { items = append(items, e.item) },
+	)
+	return items
+}
+
+// QueryPoint returns all items whose bounds contain the point.
+func (h *Hash) QueryPoint(v pixel.Vec) []interface{} {
+	var items []interface{}
+	// a point on the border between cells belongs to all of them
+	eps := pixel.V(1e-9, 1e-9).Scaled(h.cellSize)
+	h.visit(h.cellAt(v.Sub(eps)), h.cellAt(v.Add(eps)),
+		func(bounds pixel.Rect) bool { return bounds.Contains(v) },
+		func(e *hashEntry) { items = append(items, e.item) },
+	)
+	return items
+}
+
+// QueryRay returns all items whose bounds are hit by the Ray no further than maxDist from its
+// Origin, sorted by the distance.
+//
+// The Ray walks through the grid cell by cell. If the walk would be longer than checking all the
+// items, they are checked directly instead.
+func (h *Hash) QueryRay(r pixel.Ray, maxDist float64) []RayHit {
+	if r.Direction == pixel.ZV {
+		return nil
+	}
+	dir := r.Direction.Unit()
+
+	var hits []RayHit
+	h.query++
+	check := func(e *hashEntry) {
+		if e.query == h.query {
+			return
+		}
+		e.query = h.query
+		if t, ok := rayEntry(r.Origin, dir, e.bounds, maxDist); ok {
+			hits = append(hits, RayHit{Item: e.item, Distance: t})
+		}
+	}
+
+	if 2*maxDist/h.cellSize > float64(len(h.items)) {
+		for _, e := range h.items {
+			check(e)
+		}
+		sortHits(hits)
+		return hits
+	}
+
+	// walk the cells along the Ray with the DDA algorithm
+	cell := h.cellAt(r.Origin)
+	step := hashCell{1, 1}
+	next := pixel.V(math.Inf(+1), math.Inf(+1)) // distance to the next cell border in each axis
+	delta := pixel.V(math.Inf(+1), math.Inf(+1))
+	if dir.X < 0 {
+		step.x = -1
+	}
+	if dir.Y < 0 {
+		step.y = -1
+	}
+	if dir.X != 0 {
+		border := float64(cell.x) * h.cellSize
+		if dir.X > 0 {
+			border += h.cellSize
+		}
+		next.X = (border - r.Origin.X) / dir.X
+		delta.X = h.cellSize / math.Abs(dir.X)
+	}
+	if dir.Y != 0 {
+		border := float64(cell.y) * h.cellSize
+		if dir.Y > 0 {
+			border += h.cellSize
+		}
+		next.Y = (border - r.Origin.Y) / dir.Y
+		delta.Y = h.cellSize / math.Abs(dir.Y)
+	}
+	for t := 0.0; t <= maxDist; {
+		for _, e := range h.cells[cell] {
+			check(e)
+		}
+		if next.X < next.Y {
+			t, next.X, cell.x = next.X, next.X+delta.X, cell.x+step.x
+		} else {
+			t, next.Y, cell.y = next.Y, next.Y+delta.Y, cell.y+step.y
+		}
+	}
+	sortHits(hits)
+	return hits
+}
@@ -0,0 +1,296 @@
+package spatial
+
+import (
+	"fmt"
+
+	"github.com/faiface/pixel"
+)
+
+const (
+	// quadCapacity is the number of items a node holds before it splits into four children.
+	quadCapacity = 8
+	// quadMaxDepth limits the depth of the tree, so that many items at the same spot don't cause
+	// an endless splitting.
+	quadMaxDepth = 12
+)
+
+// Quadtree is an Index which recursively divides its area into four quadrants. It adapts to the
+// distribution of the items and suits worlds with clusters of small items as well as big items
+// mixed with small ones.
+//
+// Each item lives in the smallest quadrant which fully contains its bounds. Items outside the
+// area of the Quadtree are supported, but they are stored in the root and checked by each query.
+type Quadtree struct {
+	root  *quadNode
+	items map[interface{}]*quadEntry
+}
+
+var _ Index = (*Quadtree)(nil)
+
+type quadEntry struct {
+	item   interface{}
+	bounds pixel.Rect
+	node   *quadNode
+}
+
+type quadNode struct {
+	bounds   pixel.Rect
+	depth    int
+	parent   *quadNode
+	children *[4]quadNode
+	entries  []*quadEntry
+	count    int // number of entries in the whole subtree
+}
+
+// NewQuadtree creates a new empty Quadtree covering the area.
+func NewQuadtree(area pixel.Rect) *Quadtree {
+	return &Quadtree{
+		root:  &quadNode{bounds: area.Norm()},
+		items: make(map[interface{}]*quadEntry),
+	}
+}
+
+// Insert adds the item with the bounds to the Quadtree. It panics if the item already is in the
+// Quadtree.
+func (qt *Quadtree) Insert(item interface{}, bounds pixel.Rect) {
+	if _, ok := qt.items[item]; ok {
+		panic(fmt.Errorf("(%T).Insert: item %v already inserted", qt, item))
+	}
+	e := &quadEntry{item: item, bounds: bounds.Norm()}
+	qt.items[item] = e
+	qt.root.insert(e)
+}
+
+// Move changes the bounds of the item. It panics if the item isn't in the Quadtree.
+func (qt *Quadtree) Move(item interface{}, bounds pixel.Rect) {
+	e, ok := qt.items[item]
+	if !ok {
+		panic(fmt.Errorf("(%T).Move: item %v not inserted", qt, item))
+	}
+	bounds = bounds.Norm()
+	// small moves usually don't leave the quadrant, so there's no need to touch the tree
+	if n := e.node; n == qt.root || contains(n.bounds, bounds) {
+		if n.children == nil || n.childFor(bounds) == nil {
+			e.bounds = bounds
+			return
+		}
+	}
+	e.node.remove(e)
+	e.bounds = bounds
+	qt.root.insert(e)
+}
+
+// Remove removes the item from the Quadtree. It does nothing if the item isn't in the Quadtree.
+func (qt *Quadtree) Remove(item interface{}) {
+	e, ok := qt.items[item]
+	if !ok {
+		return
+	}
+	delete(qt.items, item)
+	e.node.remove(e)
+}
+
+// Bounds returns the normalized bounds of the item and whether it's in the Quadtree.
+func (qt *Quadtree) Bounds(item interface{}) (pixel.Rect, bool) {
+	e, ok := qt.items[item]
+	if !ok {
+		return pixel.Rect{}, false
+	}
+	return e.bounds, true
+}
+
+// Len returns the number of items in the Quadtree.
+func (qt *Quadtree) Len() int {
+	return len(qt.items)
+}
+
+// Clear removes all items from the Quadtree.
+func (qt *Quadtree) Clear() {
+	qt.root = &quadNode{bounds: qt.root.bounds}
+	qt.items = make(map[interface{}]*quadEntry)
+}
+
+// QueryRect returns all items whose bounds intersect the Rect.
+func (qt *Quadtree) QueryRect(r pixel.Rect) []interface{} {
+	r = r.Norm()
+	var items []interface{}
+	qt.root.query(
+		func(bounds pixel.Rect) bool { return overlaps(bounds, r) },
+		func(e *quadEntry) { items = append(items, e.item) },
+	)
+	return items
+}
+
+// QueryCircle returns all items whose bounds intersect the Circle.
+func (qt *Quadtree) QueryCircle(c pixel.Circle) []interface{} {
+	c = c.Norm()
+	var items []interface{}
+	qt.root.query(
+		func(bounds pixel.Rect) bool { return overlapsCircle(bounds, c) },
+		func(e *quadEntry) { items = append(items, e.item) },
+	)
+	return items
+}
+
+// QueryPoint returns all items whose bounds contain the point.
+func (qt *Quadtree) QueryPoint(v pixel.Vec) []interface{} {
+	var items []interface{}
+	qt.root.query(
+		func(bounds pixel.Rect) bool { return bounds.Contains(v) },
+		func(e *quadEntry) { items = append(items, e.item) },
+	)
+	return items
+}
+
+// QueryRay returns all items whose bounds are hit by the Ray no further than maxDist from its
+// Origin, sorted by the distance.
+func (qt *Quadtree) QueryRay(r pixel.Ray, maxDist float64) []RayHit {
+	if r.Direction == pixel.ZV {
+		return nil
+	}
+	dir := r.Direction.Unit()
+	var hits []RayHit
+	qt.root.query(
+		func(bounds pixel.Rect) bool {
+			_, ok := rayEntry(r.Origin, dir, bounds, maxDist)
+			return ok
+		},
+		func(e *quadEntry) {
+			t, _ := rayEntry(r.Origin, dir, e.bounds, maxDist)
+			hits = append(hits, RayHit{Item: e.item, Distance: t})
+		},
+	)
+	sortHits(hits)
+	return hits
+}
+
+// query calls found for all entries in the subtree whose bounds pass the test. Subtrees of
+// children whose area doesn't pass the test are skipped.
+func (n *quadNode) query(test func(pixel.Rect) bool, found func(*quadEntry)) {
+	for _, e := range n.entries {
+		if test(e.bounds) {
+			found(e)
+		}
+	}
+	if n.children == nil {
+		return
+	}
+	for i := range n.children {
+		child := &n.children[i]
+		if child.count > 0 && test(child.bounds) {
+			child.query(test, found)
+		}
+	}
+}
+
+// childFor returns the child which fully contains the bounds, or nil if there's none.
+func (n *quadNode) childFor(bounds pixel.Rect) *quadNode {
+	for i := range n.children {
+		if contains(n.children[i].bounds, bounds) {
+			return &n.children[i]
+		}
+	}
+	return nil
+}
+
+func (n *quadNode) insert(e *quadEntry) {
+	for n.children != nil {
+		n.count++
+		child := n.childFor(e.bounds)
+		if child == nil {
+			break
+		}
+		n = child
+	}
+	if n.children == nil {
+		n.count++
+	}
+	n.entries = append(n.entries, e)
+	e.node = n
+
+	if n.children == nil && len(n.entries) > quadCapacity && n.depth < quadMaxDepth {
+		n.split()
+	}
+}
+
+func (n *quadNode) split() {
+	min, max, center := n.bounds.Min, n.bounds.Max, n.bounds.Center()
+	n.children = &[4]quadNode{
+		{bounds: pixel.R(min.X, min.Y, center.X, center.Y)},
+		{bounds: pixel.R(center.X, min.Y, max.X, center.Y)},
+		{bounds: pixel.R(min.X, center.Y, center.X, max.Y)},
+		{bounds: pixel.R(center.X, center.Y, max.X, max.Y)},
+	}
+	for i := range n.children {
+		n.children[i].depth = n.depth + 1
+		n.children[i].parent = n
+	}
+
+	entries := n.entries
+	n.entries = nil
+	for _, e := range entries {
+		child := n.childFor(e.bounds)
+		if child == nil {
+			n.entries = append(n.entries, e)
+			continue
+		}
+		child.count++
+		child.entries = append(child.entries, e)
+		e.node = child
+	}
+	for i := range n.children {
+		child := &n.children[i]
+		if len(child.entries) > quadCapacity && child.depth < quadMaxDepth {
+			child.split()
+		}
+	}
+}
+
+func (n *quadNode) remove(e *quadEntry) {
+	for i, other := range n.entries {
+		if other == e {
+			last := len(n.entries) - 1
+			n.entries[i], n.entries[last] = n.entries[last], nil
+			n.entries = n.entries[:last]
+			break
+		}
+	}
+	e.node = nil
+
+	var merge *quadNode
+	for m := n; m != nil; m = m.parent {
+		m.count--
+		if m.children != nil && m.count <= quadCapacity {
+			merge = m
+		}
+	}
+	if merge != nil {
+		merge.merge()
+	}
+}
+
+// merge moves all entries of the subtree into the node and removes its children.
+func (n *quadNode) merge() {
+	for i := range n.children {
+		n.children[i].collect(n)
+	}
+	n.children = nil
+}
+
+func (n *quadNode) collect(into *quadNode) {
+	for _, e := range n.entries {
+		into.entries = append(into.entries, e)
+		e.node = into
+	}
+	if n.children != nil {
+		for i := range n.children {
+			n.children[i].collect(into)
+		}
+	}
+}
+
+// contains reports whether the normalized Rect outer fully contains the normalized Rect inner.
+func contains(outer, inner pixel.Rect) bool {
+	return outer.Min.X <= inner.Min.X && inner.Max.X <= outer.Max.X &&
+		outer.Min.Y <= inner.Min.Y && inner.Max.Y <= outer.Max.Y
+}
@@ -0,0 +1,116 @@
+// Package spatial implements spatial indexes for quickly finding items by their bounding Rects,
+// such as for culling sprites outside the screen or for the broadphase of collision detection.
+package spatial
+
+import (
+	"math"
+	"sort"
+
+	"github.com/faiface/pixel"
+)
+
+// Index stores items by their bounding Rects and finds the items in a given area without
+// checking them all.
+//
+// Items can be of any comparable type, usually pointers to the game objects:
+//
+//   idx := spatial.NewQuadtree(pixel.R(0, 0, 4096, 4096))
+//   for _, e := range enemies {
+//       idx.Insert(e, e.Bounds())
+//   }
+//   for _, item := range idx.QueryRect(camera) {
+//       item.(*Enemy).Draw(batch)
+//   }
+//
+// Bounds don't have to be normalized, the Index normalizes them. All queries include items which
+// only touch the queried area. Except for QueryRay, the queries return items in no particular
+// order.
+type Index interface {
+	// Insert adds the item with the bounds to the Index. It panics if the item already is in the
+	// Index.
+	Insert(item interface{}, bounds pixel.Rect)
+
+	// Move changes the bounds of the item. It panics if the item isn't in the Index.
+	Move(item interface{}, bounds pixel.Rect)
+
+	// Remove removes the item from the Index. It does nothing if the item isn't in the Index.
+	Remove(item interface{})
+
+	// Bounds returns the normalized bounds of the item and whether it's in the Index.
+	Bounds(item interface{}) (pixel.Rect, bool)
+
+	// Len returns the number of items in the Index.
+	Len() int
+
+	// Clear removes all items from the Index.
+	Clear()
+
+	// QueryRect returns all items whose bounds intersect the Rect.
+	QueryRect(r pixel.Rect) []interface{}
+
+	// QueryCircle returns all items whose bounds intersect the Circle.
+	QueryCircle(c pixel.Circle) []interface{}
+
+	// QueryPoint returns all items whose bounds contain the point.
+	QueryPoint(v pixel.Vec) []interface{}
+
+	// QueryRay returns all items whose bounds are hit by the Ray no further than maxDist from its
+	// Origin, sorted by the distance. Use math.Inf(+1) for an unlimited distance.
+	QueryRay(r pixel.Ray, maxDist float64) []RayHit
+}
+
+// RayHit is an item hit by a Ray in Index.QueryRay.
+type RayHit struct {
+	// Item is the item whose bounds were hit.
+	Item interface{}
+
+	// Distance is the distance from the Ray's Origin to the point where it enters the bounds of
+	// the Item. It is zero if the Origin lies inside the bounds.
+	Distance float64
+}
+
+// overlaps reports whether the two normalized Rects intersect or touch.
+func overlaps(a, b pixel.Rect) bool {
+	return a.Min.X <= b.Max.X && b.Min.X <= a.Max.X && a.Min.Y <= b.Max.Y && b.Min.Y <= a.Max.Y
+}
+
+// overlapsCircle reports whether the normalized Rect intersects or touches the normalized Circle.
+func overlapsCircle(r pixel.Rect, c pixel.Circle) bool {
+	closest := pixel.V(
+		pixel.Clamp(c.Center.X, r.Min.X, r.Max.X),
+		pixel.Clamp(c.Center.Y, r.Min.Y, r.Max.Y),
+	)
+	return closest.To(c.Center).SqLen() <= c.Radius*c.Radius
+}
+
+// rayEntry returns the distance along the Ray at which it enters the normalized Rect, using the
+// unit direction dir. It returns false if the Ray misses the Rect or enters it beyond maxDist.
+func rayEntry(origin, dir pixel.Vec, r pixel.Rect, maxDist float64) (float64, bool) {
+	tmin, tmax := 0.0, maxDist
+	for _, axis := range [...]struct{ o, d, min, max float64 }{
+		{origin.X, dir.X, r.Min.X, r.Max.X},
+		{origin.Y, dir.Y, r.Min.Y, r.Max.Y},
+	} {
+		if axis.d == 0 {
+			if axis.o < axis.min || axis.o > axis.max {
+				return 0, false
+			}
+			continue
+		}
+		t1, t2 := (axis.min-axis.o)/axis.d, (axis.max-axis.o)/axis.d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tmin, tmax = math.Max(tmin, t1), math.Min(tmax, t2)
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+	return tmin, true
+}
+
+func sortHits(hits []RayHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+}
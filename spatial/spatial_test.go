@@ -0,0 +1,186 @@
+package spatial_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+// naive is a reference Index which simply checks all items.
+type naive map[interface{}]pixel.Rect
+
+func (n naive) query(test func(pixel.Rect) bool) []interface{} {
+	var items []interface{}
+	for item, bounds := range n {
+		if test(bounds) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func randomRect(rnd *rand.Rand) pixel.Rect {
+	min := pixel.V(rnd.Float64()*1200-100, rnd.Float64()*1200-100)
+	size := pixel.V(rnd.Float64()*rnd.Float64()*80, rnd.Float64()*rnd.Float64()*80)
+	return pixel.Rect{Min: min, Max: min.Add(size)}
+}
+
+func indexes() map[string]func() spatial.Index {
+	return map[string]func() spatial.Index{
+		"Quadtree": func() spatial.Index { return spatial.NewQuadtree(pixel.R(0, 0, 1000, 1000)) },
+		"Hash":     func() spatial.Index { return spatial.NewHash(32) },
+	}
+}
+
+func TestIndex(t *testing.T) {
+	for name, newIndex := range indexes() {
+		t.Run(name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(0))
+			idx, ref := newIndex(), naive{}
+
+			for i := 0; i < 3000; i++ {
+				switch op := rnd.Intn(10); {
+				case op < 5 || len(ref) == 0:
+					r := randomRect(rnd)
+					idx.Insert(i, r)
+					ref[i] = r
+				case op < 8:
+					for item := range ref {
+						r := ref[item].Moved(pixel.V(rnd.Float64()*40-20, rnd.Float64()*40-20))
+						idx.Move(item, r)
+						ref[item] = r
+						break
+					}
+				default:
+					for item := range ref {
+						idx.Remove(item)
+						delete(ref, item)
+						break
+					}
+				}
+
+				if i%100 != 0 {
+					continue
+				}
+				assert.Equal(t, len(ref), idx.Len())
+
+				r := randomRect(rnd)
+				assert.ElementsMatch(t, ref.query(func(b pixel.Rect) bool {
+					return b.Min.X <= r.Max.X && r.Min.X <= b.Max.X && b.Min.Y <= r.Max.Y && r.Min.Y <= b.Max.Y
+				}), idx.QueryRect(r), "QueryRect(%v)", r)
+
+				c := pixel.C(pixel.V(rnd.Float64()*1000, rnd.Float64()*1000), rnd.Float64()*100)
+				assert.ElementsMatch(t, ref.query(func(b pixel.Rect) bool {
+					closest := pixel.V(pixel.Clamp(c.Center.X, b.Min.X, b.Max.X), pixel.Clamp(c.Center.Y, b.Min.Y, b.Max.Y))
+					return closest.To(c.Center).Len() <= c.Radius
+				}), idx.QueryCircle(c), "QueryCircle(%v)", c)
+
+				v := pixel.V(rnd.Float64()*1000, rnd.Float64()*1000)
+				assert.ElementsMatch(t, ref.query(func(b pixel.Rect) bool {
+					return b.Contains(v)
+				}), idx.QueryPoint(v), "QueryPoint(%v)", v)
+
+				ray := pixel.Ray{Origin: v, Direction: pixel.V(rnd.Float64()-0.5, rnd.Float64()-0.5)}
+				for _, maxDist := range []float64{200, math.Inf(+1)} {
+					var expected []interface{}
+					for item, b := range ref {
+						if b.Contains(ray.Origin) {
+							expected = append(expected, item)
+						} else if hit, ok := ray.CastRect(b); ok && hit.Distance <= maxDist {
+							expected = append(expected, item)
+						}
+					}
+					hits := idx.QueryRay(ray, maxDist)
+					var actual []interface{}
+					for i, hit := range hits {
+						actual = append(actual, hit.Item)
+						if i > 0 {
+							assert.LessOrEqual(t, hits[i-1].Distance, hit.Distance)
+						}
+					}
+					assert.ElementsMatch(t, expected, actual, "QueryRay(%v, %v)", ray, maxDist)
+				}
+			}
+
+			for item, r := range ref {
+				bounds, ok := idx.Bounds(item)
+				assert.True(t, ok)
+				assert.Equal(t, r, bounds)
+			}
+			idx.Clear()
+			assert.Equal(t, 0, idx.Len())
+			assert.Empty(t, idx.QueryRect(pixel.R(-1000, -1000, 2000, 2000)))
+		})
+	}
+}
+
+func TestIndex_Misuse(t *testing.T) {
+	for name, newIndex := range indexes() {
+		t.Run(name, func(t *testing.T) {
+			idx := newIndex()
+			idx.Insert("a", pixel.R(10, 10, 0, 0))
+			bounds, ok := idx.Bounds("a")
+			assert.True(t, ok)
+			assert.Equal(t, pixel.R(0, 0, 10, 10), bounds)
+
+			assert.Panics(t, func() { idx.Insert("a", pixel.R(0, 0, 1, 1)) })
+			assert.Panics(t, func() { idx.Move("b", pixel.R(0, 0, 1, 1)) })
+			assert.NotPanics(t, func() { idx.Remove("b") })
+
+			idx.Remove("a")
+			_, ok = idx.Bounds("a")
+			assert.False(t, ok)
+		})
+	}
+	assert.Panics(t, func() { spatial.NewHash(0) })
+}
+
+func TestIndex_QueryRay(t *testing.T) {
+	for name, newIndex := range indexes() {
+		t.Run(name, func(t *testing.T) {
+			idx := newIndex()
+			idx.Insert("far", pixel.R(500, 90, 520, 110))
+			idx.Insert("near", pixel.R(200, 90, 220, 110))
+			idx.Insert("around", pixel.R(0, 0, 1000, 200))
+			idx.Insert("off", pixel.R(300, 300, 320, 320))
+			// outside the area of the Quadtree
+			idx.Insert("outside", pixel.R(1500, 50, 1600, 150))
+
+			ray := pixel.Ray{Origin: pixel.V(100, 100), Direction: pixel.V(2, 0)}
+			hits := idx.QueryRay(ray, math.Inf(+1))
+			if assert.Len(t, hits, 4) {
+				assert.Equal(t, spatial.RayHit{Item: "around", Distance: 0}, hits[0])
+				assert.Equal(t, spatial.RayHit{Item: "near", Distance: 100}, hits[1])
+				assert.Equal(t, spatial.RayHit{Item: "far", Distance: 400}, hits[2])
+				assert.Equal(t, spatial.RayHit{Item: "outside", Distance: 1400}, hits[3])
+			}
+			assert.Len(t, idx.QueryRay(ray, 399), 2)
+			assert.Empty(t, idx.QueryRay(pixel.Ray{Origin: ray.Origin}, 100))
+		})
+	}
+}
+
+func BenchmarkIndex(b *testing.B) {
+	for name, newIndex := range indexes() {
+		b.Run(name, func(b *testing.B) {
+			rnd := rand.New(rand.NewSource(0))
+			idx := newIndex()
+			rects := make([]pixel.Rect, 10000)
+			for i := range rects {
+				rects[i] = randomRect(rnd)
+				idx.Insert(i, rects[i])
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				item := i % len(rects)
+				rects[item] = rects[item].Moved(pixel.V(1, 0))
+				idx.Move(item, rects[item])
+				idx.QueryRect(pixel.R(400, 400, 600, 550))
+			}
+		})
+	}
+}
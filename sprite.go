@@ -2,7 +2,8 @@ package pixel
 
 import "image/color"
 
-// Sprite is a drawable frame of a Picture. It's anchored by the center of it's Picture's frame.
+// Sprite is a drawable frame of a Picture. By default, it's anchored by the center of it's
+// Picture's frame, use SetAnchor to anchor it elsewhere.
 //
 // Frame specifies a rectangular portion of the Picture that will be drawn. For example, this
 // creates a Sprite that draws the whole Picture:
@@ -10,25 +11,31 @@ import "image/color"
 //   sprite := pixel.NewSprite(pic, pic.Bounds())
 //
 // Note, that Sprite caches the results of MakePicture from Targets it's drawn to for each Picture
-// it's set to. What it means is that using a Sprite with an unbounded number of Pictures leads to a
-// memory leak, since Sprite caches them and never forgets. In such a situation, create a new Sprite
-// for each Picture.
+// it's set to. By default, it never forgets them, so using a Sprite with an unbounded number of
+// Pictures leads to a memory leak. In such a situation, bound the cache with SetMaxCached, or call
+// ForgetPicture when a Picture is no longer used.
 type Sprite struct {
-	tri   *TrianglesData
+	tri   *IndexedTrianglesData
 	frame Rect
 	d     Drawer
 
+	anchor       Anchor
+	flipX, flipY bool
+	clip         Rect
+	clipped      bool
+
 	matrix Matrix
 	mask   RGBA
 }
 
 // NewSprite creates a Sprite from the supplied frame of a Picture.
 func NewSprite(pic Picture, frame Rect) *Sprite {
-	tri := MakeTrianglesData(6)
+	tri := MakeIndexedTrianglesData(4, []int{0, 1, 2, 0, 2, 3})
 	s := &Sprite{
 		tri: tri,
 		d:   Drawer{Triangles: tri, Cached: true},
 	}
+	s.anchor = Center
 	s.matrix = IM
 	s.mask = Alpha(1)
 	s.Set(pic, frame)
@@ -51,6 +58,25 @@ func (s *Sprite) SetCached(cached bool) {
 	s.d.Cached = cached
 }
 
+// SetMaxCached sets the maximal number of Pictures cached for each Target the Sprite is drawn to.
+// The least recently drawn Pictures are evicted and released. Zero means no limit, which is the
+// default.
+func (s *Sprite) SetMaxCached(max int) {
+	s.d.MaxCached = max
+}
+
+// ForgetPicture removes the results of MakePicture of the Picture from the Sprite's cache,
+// releasing them if possible.
+func (s *Sprite) ForgetPicture(pic Picture) {
+	s.d.ForgetPicture(pic)
+}
+
+// ForgetTarget removes everything the Sprite made by the Target, releasing the Pictures if
+// possible.
+func (s *Sprite) ForgetTarget(t Target) {
+	s.d.ForgetTarget(t)
+}
+
 // Picture returns the current Sprite's Picture.
 func (s *Sprite) Picture() Picture {
 	return s.d.Picture
@@ -61,6 +87,68 @@ func (s *Sprite) Frame() Rect {
 	return s.frame
 }
 
+// SetAnchor sets where the Sprite lies relative to the origin it's transformed from, using the same
+// convention as Rect.AlignedTo. For example, Center centers the Sprite around the origin (which is
+// the default), Top places the Sprite above the origin, so that the origin is the middle of its
+// bottom edge, and BottomLeft places it below and to the left of the origin.
+//
+// Any Anchor works, not just the predefined ones: Anchor{0.5, 0.1} puts the origin in the middle of
+// the Sprite, one tenth of its height from the bottom, which is handy for the feet of a character.
+// Rotations and scaling by the Matrix happen around the origin.
+func (s *Sprite) SetAnchor(anchor Anchor) {
+	if anchor != s.anchor {
+		s.anchor = anchor
+		s.calcData()
+	}
+}
+
+// Anchor returns the current Sprite's anchor.
+func (s *Sprite) Anchor() Anchor {
+	return s.anchor
+}
+
+// SetFlip sets whether the Sprite's frame is mirrored horizontally and vertically. Unlike
+// flipping by a Matrix with a negative scale, the Sprite keeps its position and bounds.
+func (s *Sprite) SetFlip(horizontal, vertical bool) {
+	if horizontal != s.flipX || vertical != s.flipY {
+		s.flipX, s.flipY = horizontal, vertical
+		s.calcData()
+	}
+}
+
+// Flip returns whether the Sprite is mirrored horizontally and vertically.
+func (s *Sprite) Flip() (horizontal, vertical bool) {
+	return s.flipX, s.flipY
+}
+
+// SetClip restricts drawing of the Sprite to the given Rect. The Rect is in the pixel coordinates
+// of the Target, it isn't transformed by the Matrix passed to Draw, nor by the Target's own
+// Matrix. This is useful for scrolling lists, progress bars and similar.
+func (s *Sprite) SetClip(r Rect) {
+	if !s.clipped || r != s.clip {
+		s.clip, s.clipped = r, true
+		s.calcData()
+	}
+}
+
+// ClearClip removes the clipping Rect set by SetClip.
+func (s *Sprite) ClearClip() {
+	if s.clipped {
+		s.clip, s.clipped = Rect{}, false
+		s.calcData()
+	}
+}
+
+// Clip returns the Sprite's clipping Rect and whether it's set.
+func (s *Sprite) Clip() (rect Rect, is bool) {
+	return s.clip, s.clipped
+}
+
+// Bounds returns the Rect the Sprite covers when drawn with the identity Matrix.
+func (s *Sprite) Bounds() Rect {
+	return Rect{Max: V(s.frame.W(), s.frame.H())}.AlignedTo(s.anchor)
+}
+
 // Draw draws the Sprite onto the provided Target. The Sprite will be transformed by the given Matrix.
 //
 // This method is equivalent to calling DrawColorMask with nil color mask.
@@ -95,24 +183,30 @@ func (s *Sprite) DrawColorMask(t Target, matrix Matrix, mask color.Color) {
 }
 
 func (s *Sprite) calcData() {
-	var (
-		center     = s.frame.Center()
-		horizontal = V(s.frame.W()/2, 0)
-		vertical   = V(0, s.frame.H()/2)
-	)
-
-	(*s.tri)[0].Position = Vec{}.Sub(horizontal).Sub(vertical)
-	(*s.tri)[1].Position = Vec{}.Add(horizontal).Sub(vertical)
-	(*s.tri)[2].Position = Vec{}.Add(horizontal).Add(vertical)
-	(*s.tri)[3].Position = Vec{}.Sub(horizontal).Sub(vertical)
-	(*s.tri)[4].Position = Vec{}.Add(horizontal).Add(vertical)
-	(*s.tri)[5].Position = Vec{}.Sub(horizontal).Add(vertical)
-
-	for i := range *s.tri {
-		(*s.tri)[i].Color = s.mask
-		(*s.tri)[i].Picture = center.Add((*s.tri)[i].Position)
-		(*s.tri)[i].Intensity = 1
-		(*s.tri)[i].Position = s.matrix.Project((*s.tri)[i].Position)
+	bounds := s.Bounds()
+
+	vertices := s.tri.TrianglesData
+	vertices[0].Position = bounds.Min
+	vertices[1].Position = V(bounds.Max.X, bounds.Min.Y)
+	vertices[2].Position = bounds.Max
+	vertices[3].Position = V(bounds.Min.X, bounds.Max.Y)
+
+	for i := range vertices {
+		offset := bounds.Min.To(vertices[i].Position)
+		pic := s.frame.Min.Add(offset)
+		if s.flipX {
+			pic.X = s.frame.Max.X - offset.X
+		}
+		if s.flipY {
+			pic.Y = s.frame.Max.Y - offset.Y
+		}
+
+		vertices[i].Color = s.mask
+		vertices[i].Picture = pic
+		vertices[i].Intensity = 1
+		vertices[i].ClipRect = s.clip
+		vertices[i].IsClipped = s.clipped
+		vertices[i].Position = s.matrix.Project(vertices[i].Position)
 	}
 
 	s.d.Dirty()
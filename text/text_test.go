@@ -10,6 +10,7 @@ import (
 	"golang.org/x/image/font/gofont/goregular"
 
 	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixeltest"
 	"github.com/faiface/pixel/text"
 	"github.com/golang/freetype/truetype"
 )
@@ -34,6 +35,20 @@ func TestClear(t *testing.T) {
 	}
 }
 
+func TestText_Golden(t *testing.T) {
+	txt := text.New(pixel.V(4, 20), text.Atlas7x13)
+	txt.Color = pixel.RGB(0, 0, 0)
+	fmt.Fprintln(txt, "Hello, Pixel!")
+	txt.Color = pixel.RGB(0.8, 0, 0)
+	fmt.Fprint(txt, "golden\ttext")
+
+	pd := pixeltest.Render(pixel.R(0, 0, 100, 48), func(t pixel.ComposeTarget) {
+		t.(*pixel.Raster).Clear(pixel.RGB(1, 1, 1))
+		txt.Draw(t, pixel.IM)
+	})
+	pixeltest.AssertGolden(t, "text", pd, pixeltest.Options{Tolerance: 2})
+}
+
 func BenchmarkNewAtlas(b *testing.B) {
 	runeSets := []struct {
 		name string
@@ -0,0 +1,249 @@
+package pixel
+
+import (
+	"math"
+	"sort"
+)
+
+// Triangulate splits the Polygon with the given holes into triangles using ear clipping. The
+// Polygon doesn't have to be convex, but neither the Polygon nor the holes may intersect
+// themselves or each other. Holes must lie inside the Polygon. The winding of the Polygon and the
+// holes doesn't matter.
+//
+// The result is a list of vertex indices, each three of them making a counter-clockwise triangle.
+// Indices refer to the vertices of the Polygon followed by the vertices of the holes in order:
+//
+//   square := pixel.Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(0, 10)}
+//   hole := pixel.Polygon{pixel.V(4, 4), pixel.V(6, 4), pixel.V(6, 6), pixel.V(4, 6)}
+//   square.Triangulate(hole) // indices 0-3 are the square's vertices, 4-7 are the hole's
+//
+// Degenerate triangles with zero area are left out. Time complexity is O(n^2) in the total number
+// of vertices.
+func (p Polygon) Triangulate(holes ...Polygon) []int {
+	if len(p) < 3 {
+		return nil
+	}
+
+	vertices := append(Polygon(nil), p...)
+	outer := make([]int, len(p))
+	for i := range outer {
+		outer[i] = i
+	}
+	if p.SignedArea() < 0 {
+		reverseInts(outer)
+	}
+
+	var rings [][]int
+	for _, h := range holes {
+		ring := make([]int, len(h))
+		for i := range ring {
+			ring[i] = len(vertices) + i
+		}
+		vertices = append(vertices, h...)
+		if len(h) < 3 {
+			continue
+		}
+		if h.SignedArea() > 0 {
+			reverseInts(ring)
+		}
+		rings = append(rings, ring)
+	}
+
+	// bridge the rightmost holes first, so that the bridges don't cross the holes left of them
+	maxX := func(ring []int) float64 {
+		max := math.Inf(-1)
+		for _, i := range ring {
+			max = math.Max(max, vertices[i].X)
+		}
+		return max
+	}
+	sort.SliceStable(rings, func(i, j int) bool {
+		return maxX(rings[i]) > maxX(rings[j])
+	})
+	for i, ring := range rings {
+		outer = bridgeHole(vertices, outer, ring, rings[i+1:])
+	}
+
+	return clipEars(vertices, outer)
+}
+
+// TrianglesData returns the triangulation of the Polygon with the given holes (see Triangulate)
+// as TrianglesData ready to be drawn. All vertices are white and have no Picture.
+func (p Polygon) TrianglesData(holes ...Polygon) *TrianglesData {
+	return p.makeTrianglesData(holes, func(tri *TrianglesData, i int) {})
+}
+
+// PictureTrianglesData returns the triangulation of the Polygon with the given holes (see
+// Triangulate) as TrianglesData ready to be drawn with a Picture. The Picture coordinates of each
+// vertex are its position projected by the Matrix toPicture and the Intensity is 1.
+//
+// For example, to stretch the Picture frame over the whole Polygon:
+//
+//   bounds := p.Bounds()
+//   toPicture := pixel.IM.Moved(bounds.Min.Scaled(-1)).
+//       ScaledXY(pixel.ZV, pic.Bounds().Size().ScaledXY(pixel.V(1/bounds.W(), 1/bounds.H()))).
+//       Moved(pic.Bounds().Min)
+//   tri := p.PictureTrianglesData(toPicture)
+func (p Polygon) PictureTrianglesData(toPicture Matrix, holes ...Polygon) *TrianglesData {
+	return p.makeTrianglesData(holes, func(tri *TrianglesData, i int) {
+		(*tri)[i].Picture = toPicture.Project((*tri)[i].Position)
+		(*tri)[i].Intensity = 1
+	})
+}
+
+func (p Polygon) makeTrianglesData(holes []Polygon, setup func(*TrianglesData, int)) *TrianglesData {
+	vertices := append(Polygon(nil), p...)
+	for _, h := range holes {
+		vertices = append(vertices, h...)
+	}
+	indices := p.Triangulate(holes...)
+	tri := MakeTrianglesData(len(indices))
+	for i, index := range indices {
+		(*tri)[i].Position = vertices[index]
+		setup(tri, i)
+	}
+	return tri
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// left reports whether c lies strictly left of the directed line from a to b.
+func left(a, b, c Vec) bool {
+	return a.To(b).Cross(a.To(c)) > 0
+}
+
+// leftOn reports whether c lies left of or on the directed line from a to b.
+func leftOn(a, b, c Vec) bool {
+	return a.To(b).Cross(a.To(c)) >= 0
+}
+
+// inCone reports whether the segment from the vertex a to u goes into the interior of a
+// counter-clockwise polygon, given the neighbours prev and next of the vertex.
+func inCone(prev, a, next, u Vec) bool {
+	if leftOn(a, next, prev) {
+		// convex vertex
+		return left(a, u, prev) && left(u, a, next)
+	}
+	return !(leftOn(a, u, next) && leftOn(u, a, prev))
+}
+
+// bridgeHole merges the clockwise hole into the counter-clockwise outer ring by cutting a pair of
+// coincident edges (a bridge) between the rightmost vertex of the hole and the nearest vertex of
+// the outer ring it can see. The bridge must not cross any of the other holes.
+func bridgeHole(vertices []Vec, outer, hole []int, others [][]int) []int {
+	m := 0
+	for i := range hole {
+		if vertices[hole[i]].X > vertices[hole[m]].X {
+			m = i
+		}
+	}
+	u := vertices[hole[m]]
+
+	visible := func(v Vec) bool {
+		for _, ring := range append([][]int{outer, hole}, others...) {
+			for i := range ring {
+				a, b := vertices[ring[i]], vertices[ring[(i+1)%len(ring)]]
+				if a == v || b == v || a == u || b == u {
+					continue
+				}
+				if segmentsIntersect(u, v, a, b) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	best, bestDist := -1, math.Inf(+1)
+	fallback, fallbackDist := 0, math.Inf(+1)
+	for i := range outer {
+		v := vertices[outer[i]]
+		dist := v.To(u).SqLen()
+		if dist < fallbackDist {
+			fallback, fallbackDist = i, dist
+		}
+		if dist >= bestDist {
+			continue
+		}
+		prev := vertices[outer[(i-1+len(outer))%len(outer)]]
+		next := vertices[outer[(i+1)%len(outer)]]
+		if (v == u || inCone(prev, v, next, u)) && visible(v) {
+			best, bestDist = i, dist
+		}
+	}
+	if best == -1 {
+		// the input is invalid, connect to the nearest vertex and hope for the best
+		best = fallback
+	}
+
+	merged := make([]int, 0, len(outer)+len(hole)+2)
+	merged = append(merged, outer[:best+1]...)
+	merged = append(merged, hole[m:]...)
+	merged = append(merged, hole[:m+1]...)
+	merged = append(merged, outer[best:]...)
+	return merged
+}
+
+// clipEars triangulates the counter-clockwise ring of vertex indices by repeatedly cutting off
+// its ears, the convex vertices whose triangle with the neighbours contains no other vertex.
+func clipEars(vertices []Vec, ring []int) []int {
+	n := len(ring)
+	prev, next := make([]int, n), make([]int, n)
+	for i := range ring {
+		prev[i], next[i] = (i-1+n)%n, (i+1)%n
+	}
+	pos := func(i int) Vec { return vertices[ring[i]] }
+
+	var indices []int
+	emit := func(i int) {
+		a, b, c := pos(prev[i]), pos(i), pos(next[i])
+		if a.To(b).Cross(b.To(c)) > 0 {
+			indices = append(indices, ring[prev[i]], ring[i], ring[next[i]])
+		}
+		next[prev[i]], prev[next[i]] = next[i], prev[i]
+		n--
+	}
+
+	isEar := func(i int) bool {
+		a, b, c := pos(prev[i]), pos(i), pos(next[i])
+		if a.To(b).Cross(b.To(c)) <= 0 {
+			return false
+		}
+		for j := next[next[i]]; j != prev[i]; j = next[j] {
+			v := pos(j)
+			if v == a || v == b || v == c {
+				continue
+			}
+			if leftOn(a, b, v) && leftOn(b, c, v) && leftOn(c, a, v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	i, stop := 0, 0
+	for n > 3 {
+		if isEar(i) {
+			emit(i)
+			i = next[i]
+			stop = i
+			continue
+		}
+		i = next[i]
+		if i == stop {
+			// no ear left, the input must be degenerate or self-intersecting, so cut the vertex
+			// anyway to make progress
+			emit(i)
+			i = next[i]
+			stop = i
+		}
+	}
+	if n == 3 {
+		emit(i)
+	}
+	return indices
+}
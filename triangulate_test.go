@@ -0,0 +1,137 @@
+package pixel_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertTriangulation checks that the triangles are counter-clockwise, cover the expected area and
+// lie inside the shape.
+func assertTriangulation(t *testing.T, vertices []pixel.Vec, indices []int, area float64, inside func(pixel.Vec) bool) {
+	t.Helper()
+	if !assert.Equal(t, 0, len(indices)%3) {
+		return
+	}
+	var total float64
+	for i := 0; i < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		triArea := a.To(b).Cross(a.To(c)) / 2
+		assert.Greater(t, triArea, 0.0, "triangle %v %v %v is not counter-clockwise", a, b, c)
+		total += triArea
+		centroid := a.Add(b).Add(c).Scaled(1.0 / 3)
+		assert.True(t, inside(centroid), "triangle %v %v %v lies outside", a, b, c)
+	}
+	assert.InDelta(t, area, total, 1e-9*math.Max(1, area))
+}
+
+func TestPolygon_Triangulate(t *testing.T) {
+	comb := pixel.Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(10, 10), pixel.V(8, 10), pixel.V(8, 2),
+		pixel.V(6, 2), pixel.V(6, 10), pixel.V(4, 10), pixel.V(4, 2), pixel.V(2, 2), pixel.V(2, 10), pixel.V(0, 10)}
+	collinear := pixel.Polygon{pixel.V(0, 0), pixel.V(5, 0), pixel.V(10, 0), pixel.V(10, 5), pixel.V(10, 10), pixel.V(0, 10)}
+
+	tests := []struct {
+		name    string
+		polygon pixel.Polygon
+	}{
+		{"square", square},
+		{"clockwise square", square.Reversed()},
+		{"arrow", arrow},
+		{"clockwise arrow", arrow.Reversed()},
+		{"comb", comb},
+		{"collinear", collinear},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indices := tt.polygon.Triangulate()
+			assert.Len(t, indices, 3*(len(tt.polygon)-2))
+			assertTriangulation(t, tt.polygon, indices, tt.polygon.Area(), tt.polygon.Contains)
+		})
+	}
+
+	assert.Empty(t, pixel.Polygon{pixel.V(0, 0), pixel.V(1, 1)}.Triangulate())
+	assert.Empty(t, pixel.Polygon{pixel.V(0, 0), pixel.V(1, 1), pixel.V(2, 2)}.Triangulate())
+}
+
+func TestPolygon_Triangulate_Holes(t *testing.T) {
+	outer := pixel.Polygon{pixel.V(0, 0), pixel.V(20, 0), pixel.V(20, 10), pixel.V(0, 10)}
+	holeA := pixel.Polygon{pixel.V(2, 2), pixel.V(6, 2), pixel.V(6, 8), pixel.V(2, 8)}
+	holeB := pixel.Polygon{pixel.V(14, 2), pixel.V(18, 5), pixel.V(14, 8)}
+	// a hole right behind holeB, so that the bridge of holeA must go around it
+	holeC := pixel.Polygon{pixel.V(8, 1), pixel.V(12, 1), pixel.V(12, 9), pixel.V(8, 9)}
+
+	tests := []struct {
+		name  string
+		holes []pixel.Polygon
+	}{
+		{"one hole", []pixel.Polygon{holeA}},
+		{"clockwise hole", []pixel.Polygon{holeA.Reversed()}},
+		{"two holes", []pixel.Polygon{holeA, holeB}},
+		{"three holes", []pixel.Polygon{holeA, holeC, holeB}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vertices := append(pixel.Polygon(nil), outer...)
+			area := outer.Area()
+			for _, h := range tt.holes {
+				vertices = append(vertices, h...)
+				area -= h.Area()
+			}
+			inside := func(u pixel.Vec) bool {
+				if !outer.Contains(u) {
+					return false
+				}
+				for _, h := range tt.holes {
+					if h.Contains(u) {
+						return false
+					}
+				}
+				return true
+			}
+			assertTriangulation(t, vertices, outer.Triangulate(tt.holes...), area, inside)
+		})
+	}
+}
+
+func TestPolygon_Triangulate_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		// random star-shaped polygons are simple, but usually very concave, and they always have
+		// enough room around the origin for a small hole
+		var p pixel.Polygon
+		n := 8 + rnd.Intn(30)
+		for j := 0; j < n; j++ {
+			angle := (float64(j) + rnd.Float64()*0.9) / float64(n) * 2 * math.Pi
+			p = append(p, pixel.Unit(angle).Scaled(1+rnd.Float64()*99))
+		}
+		hole := pixel.Polygon{pixel.V(0.5, 0), pixel.V(0, 0.5), pixel.V(-0.5, 0), pixel.V(0, -0.5)}
+
+		assertTriangulation(t, p, p.Triangulate(), p.Area(), p.Contains)
+
+		vertices := append(append(pixel.Polygon(nil), p...), hole...)
+		assertTriangulation(t, vertices, p.Triangulate(hole), p.Area()-hole.Area(), func(u pixel.Vec) bool {
+			return p.Contains(u) && !hole.Contains(u)
+		})
+	}
+}
+
+func TestPolygon_TrianglesData(t *testing.T) {
+	tri := arrow.TrianglesData()
+	assert.Equal(t, 9, tri.Len())
+	for _, v := range *tri {
+		assert.Contains(t, arrow, v.Position)
+		assert.Equal(t, pixel.RGBA{R: 1, G: 1, B: 1, A: 1}, v.Color)
+		assert.Equal(t, 0.0, v.Intensity)
+	}
+
+	hole := pixel.Polygon{pixel.V(4, 4), pixel.V(6, 4), pixel.V(6, 6), pixel.V(4, 6)}
+	tri = square.PictureTrianglesData(pixel.IM.Scaled(pixel.ZV, 2), hole)
+	assert.Equal(t, 3*8, tri.Len())
+	for _, v := range *tri {
+		assert.Equal(t, v.Position.Scaled(2), v.Picture)
+		assert.Equal(t, 1.0, v.Intensity)
+	}
+}
@@ -0,0 +1,3 @@
+// Package tween implements animating values over time with easing curves, and composing the
+// animations into sequences, loops and other timelines.
+package tween
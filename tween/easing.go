@@ -0,0 +1,148 @@
+package tween
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Easing maps the linear progress of an animation, going from 0 to 1, to the eased progress. The
+// eased progress must start at 0 and end at 1, but may go outside this range in between, such as
+// when overshooting.
+type Easing func(t float64) float64
+
+// Linear is the Easing which moves at a constant speed.
+func Linear(t float64) float64 {
+	return t
+}
+
+// Out turns an Easing which eases in (starts slowly) into one which eases out (ends slowly) and
+// vice versa.
+func Out(in Easing) Easing {
+	return func(t float64) float64 {
+		return 1 - in(1-t)
+	}
+}
+
+// InOut turns an Easing which eases in into one which eases in during the first half of the
+// animation and eases out during the second half.
+func InOut(in Easing) Easing {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return in(2*t) / 2
+		}
+		return 1 - in(2-2*t)/2
+	}
+}
+
+// Standard easing curves. The In variants start slowly, the Out variants end slowly and the InOut
+// variants do both.
+var (
+	InQuad    Easing = func(t float64) float64 { return t * t }
+	OutQuad          = Out(InQuad)
+	InOutQuad        = InOut(InQuad)
+
+	InCubic    Easing = func(t float64) float64 { return t * t * t }
+	OutCubic          = Out(InCubic)
+	InOutCubic        = InOut(InCubic)
+
+	InSine    Easing = func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+	OutSine          = Out(InSine)
+	InOutSine        = InOut(InSine)
+
+	InExpo    Easing = inExpo
+	OutExpo          = Out(InExpo)
+	InOutExpo        = InOut(InExpo)
+
+	InElastic    Easing = inElastic
+	OutElastic          = Out(InElastic)
+	InOutElastic        = InOut(InElastic)
+
+	InBounce           = Out(OutBounce)
+	OutBounce   Easing = outBounce
+	InOutBounce        = InOut(InBounce)
+
+	InBack    Easing = inBack
+	OutBack          = Out(InBack)
+	InOutBack        = InOut(InBack)
+)
+
+func inExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+func inElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((10*t-10.75)*2*math.Pi/3)
+}
+
+func outBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+func inBack(t float64) float64 {
+	// overshoots by 10%
+	const c = 1.70158
+	return (c+1)*t*t*t - c*t*t
+}
+
+// CubicBezier returns an Easing following the cubic Bezier curve from (0, 0) to (1, 1) with the
+// control points (x1, y1) and (x2, y2), the same as the cubic-bezier timing function in CSS.
+//
+//   ease := tween.CubicBezier(0.25, 0.1, 0.25, 1) // the CSS "ease"
+//
+// CubicBezier panics if x1 or x2 is outside of the range [0, 1], because the curve would go back
+// in time.
+func CubicBezier(x1, y1, x2, y2 float64) Easing {
+	if x1 < 0 || x1 > 1 || x2 < 0 || x2 > 1 {
+		panic(fmt.Errorf("tween.CubicBezier: x1 and x2 must be within [0, 1], got %v and %v", x1, x2))
+	}
+	curve := pixel.CubicBezier{P0: pixel.ZV, P1: pixel.V(x1, y1), P2: pixel.V(x2, y2), P3: pixel.V(1, 1)}
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		// find the parameter of the curve where it reaches x = t, the x coordinate of the curve
+		// never decreases, so bisection always works and Newton's method usually speeds it up
+		low, high, s := 0.0, 1.0, t
+		for i := 0; i < 32; i++ {
+			x := curve.At(s).X - t
+			if math.Abs(x) < 1e-9 {
+				break
+			}
+			if x < 0 {
+				low = s
+			} else {
+				high = s
+			}
+			next := s
+			if dx := curve.Derivative(s).X; dx != 0 {
+				next = s - x/dx
+			}
+			if next <= low || next >= high {
+				next = (low + high) / 2
+			}
+			s = next
+		}
+		return curve.At(s).Y
+	}
+}
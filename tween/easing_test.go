@@ -0,0 +1,69 @@
+package tween_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/faiface/pixel/tween"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasing(t *testing.T) {
+	easings := map[string]tween.Easing{
+		"Linear":       tween.Linear,
+		"InQuad":       tween.InQuad,
+		"OutQuad":      tween.OutQuad,
+		"InOutQuad":    tween.InOutQuad,
+		"InCubic":      tween.InCubic,
+		"OutCubic":     tween.OutCubic,
+		"InOutCubic":   tween.InOutCubic,
+		"InSine":       tween.InSine,
+		"OutSine":      tween.OutSine,
+		"InOutSine":    tween.InOutSine,
+		"InExpo":       tween.InExpo,
+		"OutExpo":      tween.OutExpo,
+		"InOutExpo":    tween.InOutExpo,
+		"InElastic":    tween.InElastic,
+		"OutElastic":   tween.OutElastic,
+		"InOutElastic": tween.InOutElastic,
+		"InBounce":     tween.InBounce,
+		"OutBounce":    tween.OutBounce,
+		"InOutBounce":  tween.InOutBounce,
+		"InBack":       tween.InBack,
+		"OutBack":      tween.OutBack,
+		"InOutBack":    tween.InOutBack,
+		"CubicBezier":  tween.CubicBezier(0.25, 0.1, 0.25, 1),
+	}
+	for name, ease := range easings {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, 0, ease(0), 1e-3)
+			assert.InDelta(t, 1, ease(1), 1e-3)
+		})
+	}
+
+	assert.Equal(t, 0.25, tween.InQuad(0.5))
+	assert.Equal(t, 0.75, tween.OutQuad(0.5))
+	assert.Equal(t, 0.125, tween.InOutQuad(0.25))
+	assert.Equal(t, 0.875, tween.InOutQuad(0.75))
+	assert.Less(t, tween.InBack(0.2), 0.0)
+	assert.Greater(t, tween.OutBack(0.8), 1.0)
+	// the ball hits the ground for the first time after 1/2.75 of the time
+	assert.InDelta(t, 1, tween.OutBounce(1/2.75), 1e-9)
+	assert.Less(t, tween.OutBounce(1.5/2.75), 1.0)
+}
+
+func TestCubicBezier(t *testing.T) {
+	linear := tween.CubicBezier(0, 0, 1, 1)
+	for _, x := range []float64{0, 0.1, 0.33, 0.5, 0.9, 1} {
+		assert.InDelta(t, x, linear(x), 1e-6, fmt.Sprint(x))
+	}
+
+	// symmetric curves pass through the middle
+	assert.InDelta(t, 0.5, tween.CubicBezier(0.42, 0, 0.58, 1)(0.5), 1e-6)
+
+	// the CSS "ease" at 25% of the time is at about 41% of the way
+	assert.InDelta(t, 0.4094, tween.CubicBezier(0.25, 0.1, 0.25, 1)(0.25), 1e-3)
+
+	assert.Panics(t, func() { tween.CubicBezier(-0.1, 0, 1, 1) })
+	assert.Panics(t, func() { tween.CubicBezier(0, 0, 1.1, 1) })
+}
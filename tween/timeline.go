@@ -0,0 +1,73 @@
+package tween
+
+// Timeline plays an Animation as the time goes by.
+//
+//   tl := tween.NewTimeline(tween.Vec(&pos, start, end, 2, tween.OutElastic))
+//   for !win.Closed() {
+//       dt := time.Since(last).Seconds()
+//       last = time.Now()
+//       tl.Update(dt)
+//       // draw at pos...
+//   }
+type Timeline struct {
+	anim    Animation
+	elapsed float64
+
+	// Speed multiplies the time passed to Update. It's 1 by default, 2 plays the Animation twice
+	// as fast and negative values play it backward.
+	Speed float64
+}
+
+// NewTimeline creates a new Timeline playing the Animation from its start. The Animation is
+// immediately set to its first state.
+func NewTimeline(a Animation) *Timeline {
+	tl := &Timeline{anim: a, Speed: 1}
+	tl.Seek(0)
+	return tl
+}
+
+// Update advances the Timeline by dt seconds (multiplied by the Speed) and updates the Animation.
+// It returns whether the Animation is finished.
+func (tl *Timeline) Update(dt float64) (done bool) {
+	tl.Seek(tl.elapsed + dt*tl.Speed)
+	return tl.Done()
+}
+
+// Seek moves the Timeline to the time t seconds from the start of the Animation. The time is
+// clamped to the duration of the Animation.
+func (tl *Timeline) Seek(t float64) {
+	if t < 0 {
+		t = 0
+	}
+	if d := tl.anim.Duration(); t > d {
+		t = d
+	}
+	tl.elapsed = t
+	tl.anim.Seek(t)
+}
+
+// Reset moves the Timeline back to the start of the Animation.
+func (tl *Timeline) Reset() {
+	tl.Seek(0)
+}
+
+// Elapsed returns the time in seconds from the start of the Animation.
+func (tl *Timeline) Elapsed() float64 {
+	return tl.elapsed
+}
+
+// Progress returns the elapsed time relative to the duration of the Animation, going from 0 to 1.
+// It's always 0 for infinite Animations.
+func (tl *Timeline) Progress() float64 {
+	d := tl.anim.Duration()
+	if d <= 0 {
+		return 1
+	}
+	return tl.elapsed / d
+}
+
+// Done returns whether the Timeline has reached the end of the Animation. Infinite Animations are
+// never done.
+func (tl *Timeline) Done() bool {
+	return tl.elapsed >= tl.anim.Duration()
+}
@@ -0,0 +1,243 @@
+package tween
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Animation is anything that changes over a period of time.
+//
+// Animations are seekable: the state of an Animation depends only on the time it was last seeked
+// to, not on the way it got there. This makes them easy to compose into Sequences, loops and
+// other timelines. Use a Timeline to play an Animation.
+type Animation interface {
+	// Duration returns the length of the Animation in seconds. It may be infinite.
+	Duration() float64
+
+	// Seek sets the Animation to the state at the time t seconds from its start. Times before
+	// the start and after the end set the Animation to its first or final state respectively.
+	Seek(t float64)
+}
+
+// Tween is an Animation of a single value from a start to an end, progressing according to an
+// Easing.
+type Tween struct {
+	duration float64
+	ease     Easing
+	apply    func(t float64)
+}
+
+// Func creates a Tween, which calls apply with the eased progress going from 0 to 1 over the
+// duration in seconds. If ease is nil, Linear is used.
+//
+// This is the most general Tween, use it to animate values of any type:
+//
+//   tween.Func(0.5, tween.OutQuad, func(t float64) {
+//       sprite.Set(sheet, frames[int(t*float64(len(frames)-1))])
+//   })
+func Func(duration float64, ease Easing, apply func(t float64)) *Tween {
+	if ease == nil {
+		ease = Linear
+	}
+	return &Tween{duration: duration, ease: ease, apply: apply}
+}
+
+// Float creates a Tween which animates the float64 pointed to by the target from one value to
+// another over the duration in seconds.
+func Float(target *float64, from, to, duration float64, ease Easing) *Tween {
+	return Func(duration, ease, func(t float64) {
+		*target = from + (to-from)*t
+	})
+}
+
+// Vec creates a Tween which animates the Vec pointed to by the target from one value to another
+// over the duration in seconds.
+//
+//   tween.Vec(&player.Pos, player.Pos, door, 1.5, tween.InOutCubic)
+func Vec(target *pixel.Vec, from, to pixel.Vec, duration float64, ease Easing) *Tween {
+	return Func(duration, ease, func(t float64) {
+		*target = pixel.Lerp(from, to, t)
+	})
+}
+
+// RGBA creates a Tween which animates the RGBA color pointed to by the target from one value to
+// another over the duration in seconds. The components are interpolated independently.
+func RGBA(target *pixel.RGBA, from, to pixel.RGBA, duration float64, ease Easing) *Tween {
+	return Func(duration, ease, func(t float64) {
+		*target = from.Scaled(1 - t).Add(to.Scaled(t))
+	})
+}
+
+// Rect creates a Tween which animates the Rect pointed to by the target from one value to another
+// over the duration in seconds. The corners are interpolated independently.
+func Rect(target *pixel.Rect, from, to pixel.Rect, duration float64, ease Easing) *Tween {
+	return Func(duration, ease, func(t float64) {
+		*target = pixel.Rect{
+			Min: pixel.Lerp(from.Min, to.Min, t),
+			Max: pixel.Lerp(from.Max, to.Max, t),
+		}
+	})
+}
+
+// Matrix creates a Tween which animates the Matrix pointed to by the target from one value to
+// another over the duration in seconds. The Matrices are interpolated with Matrix.Lerp, so
+// rotations stay rotations along the way.
+func Matrix(target *pixel.Matrix, from, to pixel.Matrix, duration float64, ease Easing) *Tween {
+	return Func(duration, ease, func(t float64) {
+		*target = from.Lerp(to, t)
+	})
+}
+
+// Duration returns the duration of the Tween in seconds.
+func (tw *Tween) Duration() float64 {
+	return tw.duration
+}
+
+// Seek sets the value to the state at the time t seconds from the start of the Tween.
+func (tw *Tween) Seek(t float64) {
+	progress := 1.0
+	if tw.duration > 0 {
+		progress = pixel.Clamp(t/tw.duration, 0, 1)
+	} else if t < 0 {
+		progress = 0
+	}
+	// the ends are exact, so that the final value is precisely the target value
+	switch progress {
+	case 0, 1:
+		tw.apply(progress)
+	default:
+		tw.apply(tw.ease(progress))
+	}
+}
+
+// Sequence returns an Animation which plays the Animations one after another.
+//
+//   tween.Sequence(
+//       tween.Vec(&pos, a, b, 1, tween.OutBack),
+//       tween.Delay(0.5),
+//       tween.RGBA(&col, pixel.RGB(1, 1, 1), pixel.Alpha(0), 0.3, nil),
+//   )
+func Sequence(animations ...Animation) Animation {
+	return sequence(animations)
+}
+
+type sequence []Animation
+
+func (s sequence) Duration() float64 {
+	var d float64
+	for _, a := range s {
+		d += a.Duration()
+	}
+	return d
+}
+
+func (s sequence) Seek(t float64) {
+	// Animations of the same value later in the sequence must not overwrite the current state,
+	// so the ones which didn't start yet are rewound first (in reverse, so that the earliest
+	// one wins), then the ones which did start are played
+	starts := make([]float64, len(s))
+	var start float64
+	for i, a := range s {
+		starts[i] = start
+		start += a.Duration()
+	}
+	for i := len(s) - 1; i >= 0; i-- {
+		if t < starts[i] {
+			s[i].Seek(t - starts[i])
+		}
+	}
+	for i, a := range s {
+		if t >= starts[i] {
+			a.Seek(t - starts[i])
+		}
+	}
+}
+
+// Parallel returns an Animation which plays all of the Animations at the same time. It lasts as
+// long as the longest of them.
+func Parallel(animations ...Animation) Animation {
+	return parallel(animations)
+}
+
+type parallel []Animation
+
+func (p parallel) Duration() float64 {
+	var d float64
+	for _, a := range p {
+		d = math.Max(d, a.Duration())
+	}
+	return d
+}
+
+func (p parallel) Seek(t float64) {
+	for _, a := range p {
+		a.Seek(t)
+	}
+}
+
+// Delay returns an Animation which does nothing for the duration in seconds. Use it to make
+// pauses in a Sequence.
+func Delay(duration float64) Animation {
+	return delay(duration)
+}
+
+type delay float64
+
+func (d delay) Duration() float64 { return float64(d) }
+func (d delay) Seek(float64)      {}
+
+// Yoyo returns an Animation which plays the Animation forward and then backward. It lasts twice
+// as long as the Animation.
+func Yoyo(a Animation) Animation {
+	return yoyo{a}
+}
+
+type yoyo struct {
+	a Animation
+}
+
+func (y yoyo) Duration() float64 {
+	return 2 * y.a.Duration()
+}
+
+func (y yoyo) Seek(t float64) {
+	d := y.a.Duration()
+	if t <= d {
+		y.a.Seek(t)
+		return
+	}
+	y.a.Seek(2*d - t)
+}
+
+// Loop returns an Animation which plays the Animation the given number of times in a row. If
+// the count is zero or negative, it loops forever and its duration is infinite.
+//
+//   tween.Loop(tween.Yoyo(tween.Float(&glow, 0, 1, 0.8, tween.InOutSine)), 0) // pulses forever
+func Loop(a Animation, count int) Animation {
+	return loop{a, count}
+}
+
+type loop struct {
+	a     Animation
+	count int
+}
+
+func (l loop) Duration() float64 {
+	if l.count <= 0 {
+		return math.Inf(+1)
+	}
+	return float64(l.count) * l.a.Duration()
+}
+
+func (l loop) Seek(t float64) {
+	d := l.a.Duration()
+	switch {
+	case t <= 0 || d <= 0:
+		l.a.Seek(t)
+	case t >= l.Duration():
+		l.a.Seek(d)
+	default:
+		l.a.Seek(math.Mod(t, d))
+	}
+}
@@ -0,0 +1,129 @@
+package tween_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/tween"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTween(t *testing.T) {
+	var (
+		f   float64
+		v   pixel.Vec
+		col pixel.RGBA
+		r   pixel.Rect
+		m   pixel.Matrix
+	)
+	anim := tween.Parallel(
+		tween.Float(&f, 10, 20, 2, nil),
+		tween.Vec(&v, pixel.V(0, 0), pixel.V(10, -10), 2, tween.Linear),
+		tween.RGBA(&col, pixel.RGB(1, 0, 0), pixel.RGB(0, 0, 1), 2, nil),
+		tween.Rect(&r, pixel.R(0, 0, 10, 10), pixel.R(10, 10, 30, 30), 2, nil),
+		tween.Matrix(&m, pixel.IM, pixel.IM.Rotated(pixel.ZV, math.Pi/2).Moved(pixel.V(4, 0)), 2, nil),
+	)
+	assert.Equal(t, 2.0, anim.Duration())
+
+	anim.Seek(1)
+	assert.Equal(t, 15.0, f)
+	assert.Equal(t, pixel.V(5, -5), v)
+	assert.Equal(t, pixel.RGBA{R: 0.5, B: 0.5, A: 1}, col)
+	assert.Equal(t, pixel.R(5, 5, 20, 20), r)
+	// halfway rotation, not a squashed matrix
+	half := pixel.IM.Rotated(pixel.ZV, math.Pi/4).Moved(pixel.V(2, 0))
+	for i := range m {
+		assert.InDelta(t, half[i], m[i], 1e-9)
+	}
+
+	anim.Seek(-1)
+	assert.Equal(t, 10.0, f)
+	anim.Seek(5)
+	assert.Equal(t, 20.0, f)
+	assert.Equal(t, pixel.R(10, 10, 30, 30), r)
+
+	tw := tween.Float(&f, 0, 1, 1, tween.InQuad)
+	tw.Seek(0.5)
+	assert.Equal(t, 0.25, f)
+
+	// a zero-length Tween jumps right to the end
+	tween.Float(&f, 0, 1, 0, nil).Seek(0)
+	assert.Equal(t, 1.0, f)
+}
+
+func TestSequence(t *testing.T) {
+	var x, y float64
+	anim := tween.Sequence(
+		tween.Float(&x, 0, 10, 1, nil),
+		tween.Delay(1),
+		tween.Float(&x, 10, 20, 1, nil),
+		tween.Float(&y, 0, 5, 1, nil),
+	)
+	assert.Equal(t, 4.0, anim.Duration())
+
+	for _, tt := range []struct{ t, x, y float64 }{
+		{0, 0, 0},
+		{0.5, 5, 0},
+		{1.5, 10, 0},
+		{2.5, 15, 0},
+		{3.5, 20, 2.5},
+		{4, 20, 5},
+		// backward, everything after the current time is rewound
+		{0.5, 5, 0},
+	} {
+		anim.Seek(tt.t)
+		assert.Equal(t, tt.x, x, "x at %v", tt.t)
+		assert.Equal(t, tt.y, y, "y at %v", tt.t)
+	}
+}
+
+func TestYoyoAndLoop(t *testing.T) {
+	var x float64
+	yoyo := tween.Yoyo(tween.Float(&x, 0, 10, 1, nil))
+	assert.Equal(t, 2.0, yoyo.Duration())
+	yoyo.Seek(1.25)
+	assert.Equal(t, 7.5, x)
+	yoyo.Seek(3)
+	assert.Equal(t, 0.0, x)
+
+	loop := tween.Loop(tween.Float(&x, 0, 10, 1, nil), 3)
+	assert.Equal(t, 3.0, loop.Duration())
+	loop.Seek(2.5)
+	assert.Equal(t, 5.0, x)
+	loop.Seek(3)
+	assert.Equal(t, 10.0, x)
+
+	forever := tween.Loop(yoyo, 0)
+	assert.True(t, math.IsInf(forever.Duration(), +1))
+	forever.Seek(1000.5)
+	assert.Equal(t, 5.0, x)
+}
+
+func TestTimeline(t *testing.T) {
+	var x float64
+	tl := tween.NewTimeline(tween.Sequence(tween.Delay(1), tween.Float(&x, 0, 10, 1, nil)))
+	assert.Equal(t, 0.0, x)
+
+	assert.False(t, tl.Update(1.5))
+	assert.Equal(t, 5.0, x)
+	assert.Equal(t, 0.75, tl.Progress())
+
+	assert.True(t, tl.Update(1))
+	assert.Equal(t, 10.0, x)
+	assert.Equal(t, 2.0, tl.Elapsed())
+	assert.True(t, tl.Done())
+
+	tl.Speed = -2
+	assert.False(t, tl.Update(0.25))
+	assert.Equal(t, 5.0, x)
+
+	tl.Reset()
+	assert.Equal(t, 0.0, tl.Elapsed())
+	assert.False(t, tl.Done())
+
+	forever := tween.NewTimeline(tween.Loop(tween.Float(&x, 0, 10, 1, nil), 0))
+	assert.False(t, forever.Update(1e6+0.5))
+	assert.Equal(t, 5.0, x)
+	assert.Equal(t, 0.0, forever.Progress())
+}